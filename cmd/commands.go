@@ -1,12 +1,16 @@
 package cmd
 
 import (
-	"duocli/internal/database"
 	"duocli/internal/exercises"
+	"duocli/internal/grammar"
 	"duocli/internal/models"
+	"duocli/internal/progress"
+	"duocli/internal/store"
 	"duocli/internal/ui"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -21,7 +25,7 @@ var startCmd = &cobra.Command{
 		ensureUser()
 		
 		if len(args) == 0 {
-			ui.ShowLessons()
+			ui.ShowLessons(appStore, currentUser.ID)
 			return
 		}
 		
@@ -32,18 +36,18 @@ var startCmd = &cobra.Command{
 		}
 		
 		// Check if lesson exists and is unlocked
-		var lesson models.Lesson
-		if err := database.DB.First(&lesson, uint(lessonID)).Error; err != nil {
+		lesson, err := appStore.Lesson(uint(lessonID))
+		if err != nil {
 			color.Red("❌ Lesson not found!")
 			return
 		}
 		
-		if !lesson.IsCompleted && !isLessonUnlocked(lesson) {
+		if !progress.IsCompleted(appStore, currentUser.ID, lesson.ID) && !progress.IsUnlocked(appStore, currentUser.ID, lesson) {
 			color.Red("🔒 This lesson is locked! Complete previous lessons first.")
 			return
 		}
 		
-		err = exercises.StartLesson(currentUser.ID, uint(lessonID))
+		err = exercises.StartLesson(appStore, currentUser.ID, uint(lessonID))
 		if err != nil {
 			color.Red("❌ Error starting lesson: %v", err)
 		}
@@ -53,10 +57,10 @@ var startCmd = &cobra.Command{
 var profileCmd = &cobra.Command{
 	Use:   "profile",
 	Short: "Show user profile and progress",
-	Long:  `Display detailed information about your learning progress`,
+	Long:  `Display detailed information about your learning progress, or manage profiles with the subcommands`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ensureUser()
-		ui.ShowUserProfile(currentUser.ID)
+		ui.ShowUserProfile(appStore, currentUser.ID)
 	},
 }
 
@@ -65,21 +69,39 @@ var lessonsCmd = &cobra.Command{
 	Short: "List all available lessons",
 	Long:  `Show all lessons with their completion status and requirements`,
 	Run: func(cmd *cobra.Command, args []string) {
-		ui.ShowLessons()
+		ensureUser()
+		ui.ShowLessons(appStore, currentUser.ID)
 	},
 }
 
+var (
+	vocabWeak     bool
+	vocabMastered bool
+)
+
 var vocabCmd = &cobra.Command{
 	Use:   "vocab [category]",
 	Short: "Show vocabulary words",
-	Long:  `Display vocabulary words, optionally filtered by category`,
+	Long:  `Display vocabulary words, optionally filtered by category. With --weak or --mastered, list only the words you know least or best, sorted by strength.`,
 	Args:  cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		category := ""
 		if len(args) > 0 {
 			category = args[0]
 		}
-		ui.ShowVocabulary(category)
+		ensureUser()
+
+		filter := ui.AllWords
+		switch {
+		case vocabWeak && vocabMastered:
+			return errors.New("use either --weak or --mastered, not both")
+		case vocabWeak:
+			filter = ui.WeakWords
+		case vocabMastered:
+			filter = ui.MasteredWords
+		}
+		ui.ShowVocabulary(appStore, currentUser.ID, category, filter)
+		return nil
 	},
 }
 
@@ -89,30 +111,223 @@ var statsCmd = &cobra.Command{
 	Long:  `Display detailed statistics about your learning progress`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ensureUser()
-		ui.ShowStats(currentUser.ID)
+		ui.ShowStats(appStore, currentUser.ID)
+	},
+}
+
+var reviewLimit int
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review items that are due",
+	Long:  `Run a spaced-repetition session over the exercises and words that are due for review`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ensureUser()
+
+		if err := exercises.StartReview(appStore, currentUser.ID, reviewLimit); err != nil {
+			return fmt.Errorf("error starting review: %w", err)
+		}
+		return nil
+	},
+}
+
+var drillOpts exercises.DrillOptions
+
+var drillCmd = &cobra.Command{
+	Use:   "drill",
+	Short: "Timed speed drill",
+	Long:  `Race the clock through vocabulary and exercises from lessons you have completed. Faster answers earn bonus XP.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return drillOpts.Validate()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ensureUser()
+
+		if err := exercises.StartDrill(appStore, currentUser.ID, drillOpts); err != nil {
+			return fmt.Errorf("error starting drill: %w", err)
+		}
+		return nil
+	},
+}
+
+var (
+	genderCount     int
+	genderStatsOnly bool
+)
+
+var drillGenderCmd = &cobra.Command{
+	Use:   "gender",
+	Short: "Practise noun genders (der, die, das)",
+	Long:  `Quiz the article of nouns from the vocabulary, starting with the ones you miss most, then show your accuracy per gender and the articles you mix up.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ensureUser()
+
+		if !genderStatsOnly {
+			if err := exercises.StartGenderDrill(appStore, currentUser.ID, genderCount); err != nil {
+				return fmt.Errorf("error starting gender drill: %w", err)
+			}
+		}
+
+		ui.ShowGenderStats(appStore, currentUser.ID)
+		return nil
+	},
+}
+
+var conjugatePrefix string
+
+var conjugateCmd = &cobra.Command{
+	Use:   "conjugate <verb>",
+	Short: "Show the conjugation table of a verb",
+	Long: `Print the present, Präteritum and Perfekt forms of a German verb. Regular verbs
+are conjugated by rule and common irregular verbs come from a built-in table.
+Separable verbs built on a verb it knows (ankommen, aufmachen) are detected;
+for other separable verbs pass the prefix with --prefix.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Verbs from the vocabulary know their separable prefix
+		prefix := conjugatePrefix
+		if prefix == "" {
+			words, _ := appStore.Words(store.WordQuery{German: args[0], PartOfSpeech: "verb"})
+			if len(words) > 0 {
+				prefix = words[0].SeparablePrefix
+			}
+		}
+
+		conjugation, err := grammar.ConjugateSeparable(args[0], prefix)
+		if err != nil {
+			if errors.Is(err, grammar.ErrUnknownPrefix) {
+				color.Yellow("💡 Name a separable prefix with --prefix, e.g. --prefix ab")
+			}
+			return err
+		}
+
+		ui.ShowConjugation(conjugation)
+		return nil
+	},
+}
+
+var (
+	declinePlural    string
+	declineAdjective string
+)
+
+var declineCmd = &cobra.Command{
+	Use:   "decline <noun> | decline <der|die|das> <noun>",
+	Short: "Show a noun declined in every case",
+	Long: `Print a noun with definite and indefinite articles in the nominative,
+accusative, dative and genitive. Nouns from the vocabulary are found by name;
+for any other noun give its article, e.g. 'duocli decline der Tisch'. Add an
+adjective with --adjective to see its endings, and the plural with --plural.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gender, noun, ok := grammar.SplitArticle(strings.Join(args, " "))
+		plural := declinePlural
+		if !ok {
+			word, found := findNoun(args[0])
+			if !found {
+				return fmt.Errorf("%q is not in the vocabulary, give its article, e.g. 'duocli decline der %s'", args[0], args[0])
+			}
+			gender, noun = word.Gender, word.German
+			if plural == "" {
+				plural = word.Plural
+			}
+		}
+
+		ui.ShowDeclension(gender, noun, plural, declineAdjective)
+		return nil
+	},
+}
+
+// findNoun looks a bare noun up in the vocabulary to learn its gender and
+// plural
+func findNoun(noun string) (models.Vocabulary, bool) {
+	words, err := appStore.Words(store.WordQuery{German: noun, Nouns: true})
+	if err != nil || len(words) == 0 {
+		return models.Vocabulary{}, false
+	}
+	return words[0], true
+}
+
+var matchCmd = &cobra.Command{
+	Use:   "match <category>",
+	Short: "Match German words with their meanings",
+	Long:  `Match five words from a vocabulary category with their shuffled English meanings, e.g. "1c 2a 3e 4b 5d". Every correct pair earns XP.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ensureUser()
+
+		if err := exercises.StartMatch(appStore, currentUser.ID, args[0]); err != nil {
+			return fmt.Errorf("error starting match: %w", err)
+		}
+		return nil
+	},
+}
+
+var practiceCmd = &cobra.Command{
+	Use:   "practice",
+	Short: "Practise outside of lessons",
+}
+
+var (
+	practiceCount int
+	practiceMode  string
+)
+
+var practiceVocabCmd = &cobra.Command{
+	Use:   "vocab [category]",
+	Short: "Practise vocabulary in both directions",
+	Long:  `Translate words from a vocabulary category (or all words) from German to English, English to German, or fill them into their example sentences. Words you miss most come first, and every answer is tracked per word.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ensureUser()
+
+		valid := false
+		for _, mode := range exercises.PracticeModes {
+			valid = valid || mode == practiceMode
+		}
+		if !valid {
+			return fmt.Errorf("unknown mode %q, use one of: %s", practiceMode, strings.Join(exercises.PracticeModes, ", "))
+		}
+
+		category := ""
+		if len(args) > 0 {
+			category = args[0]
+		}
+		if err := exercises.StartVocabPractice(appStore, currentUser.ID, category, practiceCount, practiceMode); err != nil {
+			return fmt.Errorf("error starting practice: %w", err)
+		}
+		return nil
 	},
 }
 
 var resetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset all progress (dangerous!)",
-	Long:  `Reset all user progress and start fresh. This cannot be undone!`,
+	Long:  `Reset the active profile's progress and start fresh. This cannot be undone!`,
 	Run: func(cmd *cobra.Command, args []string) {
-		color.Red("⚠️  WARNING: This will delete ALL your progress!")
+		ensureUser()
+
+		color.Red("⚠️  WARNING: This will delete ALL progress for %s!", currentUser.Name)
 		color.Yellow("This action cannot be undone. Are you sure? (type 'yes' to confirm)")
 		
-		var response string
-		_, err := fmt.Scan(&response)
-		if err != nil || response != "yes" {
+		if !confirmed() {
 			color.Green("✅ Reset cancelled.")
 			return
 		}
 		
-		// Reset database
-		database.DB.Exec("DELETE FROM progresses")
-		database.DB.Exec("DELETE FROM users")
-		database.DB.Exec("UPDATE lessons SET is_completed = false")
-		
+		// Reset this user's progress
+		if err := appStore.ResetProgress(currentUser.ID); err != nil {
+			color.Red("❌ Failed to reset progress: %v", err)
+			return
+		}
+		currentUser.Level = 1
+		currentUser.XP = 0
+		currentUser.Streak = 0
+		if err := appStore.SaveUser(currentUser); err != nil {
+			color.Red("❌ Failed to reset profile: %v", err)
+			return
+		}
+
 		color.Green("✅ All progress has been reset!")
 	},
 }
\ No newline at end of file
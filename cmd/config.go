@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"duocli/internal/config"
+	"duocli/internal/console"
+	"duocli/internal/ui"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or change app-wide configuration",
+	Long: `Read and write config.toml, which holds pass marks, XP per answer, pacing,
+hints, the colour theme, the daily goal and the course. It lives in
+$XDG_CONFIG_HOME/duocli/config.toml (~/.config/duocli/config.toml), or
+wherever DUOCLI_CONFIG points. Per-profile settings such as hearts are
+changed with "duocli settings".`,
+	// Replaces the root setup: these commands need no database, and they
+	// must still run when config.toml is broken so that it can be repaired
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		c, err := config.LoadLenient(path)
+		if err != nil {
+			warnBrokenConfig(err)
+		}
+		appConfig = c
+		ui.Configure(appConfig)
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		showConfig()
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every configuration key with its value",
+	Run: func(cmd *cobra.Command, args []string) {
+		showConfig()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a configuration key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := appConfig.Get(args[0])
+		if err != nil {
+			return err
+		}
+		console.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a configuration key and save config.toml",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+
+		// Start from the file rather than appConfig, which scripting flags
+		// may have changed for this run
+		c, err := config.LoadLenient(path)
+		if err != nil {
+			color.Yellow("Saving keeps the settings that could be read; the rest go back to their defaults.")
+		}
+		if err := c.Set(args[0], args[1]); err != nil {
+			return err
+		}
+		if err := c.Save(path); err != nil {
+			return fmt.Errorf("failed to save %s: %w", path, err)
+		}
+
+		value, _ := c.Get(args[0])
+		color.Green("✅ %s is now %s", args[0], value)
+		return nil
+	},
+}
+
+func showConfig() {
+	path, _ := config.Path()
+
+	console.Println("\n" + strings.Repeat("=", 60))
+	color.Cyan("⚙️  CONFIGURATION")
+	color.White("%s", path)
+	console.Println(strings.Repeat("=", 60))
+
+	for _, setting := range config.Settings {
+		value, _ := appConfig.Get(setting.Key)
+		color.White("%-22s %-10s %s", setting.Key, value, color.YellowString(setting.Description))
+	}
+
+	console.Println(strings.Repeat("=", 60))
+}
+
+// warnBrokenConfig reports what of config.toml could not be used
+func warnBrokenConfig(err error) {
+	for _, line := range strings.Split(err.Error(), "\n") {
+		color.Yellow("⚠️  %s", line)
+	}
+}
+
+// loadConfig reads config.toml into appConfig
+func loadConfig() error {
+	path, err := config.Path()
+	if err != nil {
+		return err
+	}
+
+	c, err := config.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	appConfig = c
+	return nil
+}
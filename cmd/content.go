@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"duocli/internal/content"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	contentForce         bool
+	contentLintInstalled bool
+)
+
+var contentCmd = &cobra.Command{
+	Use:   "content",
+	Short: "Manage course content packs",
+	Long:  `Import and inspect the content packs that provide lessons, exercises and vocabulary`,
+}
+
+var contentImportCmd = &cobra.Command{
+	Use:   "import <path>",
+	Short: "Import a content pack file or directory",
+	Long: `Import lessons, exercises and vocabulary from a JSON content pack, or from every
+pack in a directory. Existing content is updated in place by its stable keys,
+so learner progress is kept. Packs that are not newer than the installed
+version are skipped unless --force is given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		packs, err := content.Load(args[0])
+		if err != nil {
+			color.Red("❌ Failed to load content: %v", err)
+			os.Exit(1)
+		}
+
+		for _, pack := range packs {
+			report, err := content.Import(appStore, pack, contentForce)
+			if err != nil {
+				color.Red("❌ Failed to import %s: %v", pack.Name, err)
+				os.Exit(1)
+			}
+
+			if report.Skipped {
+				color.Yellow("⏭️  %s v%d is already installed (use --force to re-import)", report.Pack, report.Version)
+				continue
+			}
+
+			color.Green("✅ Imported %s v%d", report.Pack, report.Version)
+			color.White("   Lessons:    %d new, %d updated, %d removed", report.LessonsCreated, report.LessonsUpdated, report.LessonsRemoved)
+			color.White("   Exercises:  %d new, %d updated, %d removed", report.ExercisesCreated, report.ExercisesUpdated, report.ExercisesRemoved)
+			color.White("   Vocabulary: %d new, %d updated", report.VocabCreated, report.VocabUpdated)
+		}
+	},
+}
+
+var contentLintCmd = &cobra.Command{
+	Use:   "lint [path]",
+	Short: "Validate content packs",
+	Long: `Check content packs for problems such as lessons without exercises, multiple
+choice answers missing from their options, gaps in lesson or exercise order,
+fill-in-the-blank questions without a blank and references to unknown
+vocabulary. Without a path the built-in packs are checked; --installed checks the
+content installed in the database instead. Exits non-zero when issues are found.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var packs []content.Pack
+		var issues []content.Issue
+		var err error
+
+		switch {
+		case contentLintInstalled:
+			packs, issues = content.FromStore(appStore)
+		case len(args) > 0:
+			packs, err = content.Load(args[0])
+		default:
+			packs, err = content.Embedded()
+		}
+
+		if err != nil {
+			color.Red("❌ Failed to load content: %v", err)
+			os.Exit(1)
+		}
+
+		issues = append(issues, content.Lint(packs)...)
+		if len(issues) == 0 {
+			color.Green("✅ %d pack(s) checked, no issues found", len(packs))
+			return
+		}
+
+		for _, issue := range issues {
+			color.Red("❌ %s", issue)
+		}
+		color.Yellow("\n%d issue(s) found", len(issues))
+		os.Exit(1)
+	},
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/database"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// dbPath and dbConn are the database the db subcommands work on. They are
+// the one place that uses *gorm.DB directly: migrations change the schema
+// that store.Store hides, and must run before a store can be trusted.
+var (
+	dbPath string
+	dbConn *gorm.DB
+)
+
+var rollbackSteps int
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect and migrate the database schema",
+	Long: `Show which schema migrations have been applied to the database, apply pending
+ones, or roll the latest back. Other commands migrate automatically when they
+open the database; the database is backed up next to itself before any
+migration or rollback runs.`,
+	// Replaces the root hook so the database is opened without migrating it
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		if err := setupScripting(os.Stdin, os.Stdout, os.Stderr); err != nil {
+			return err
+		}
+
+		path, _, err := database.Path(dbFlag)
+		if err != nil {
+			return err
+		}
+		db, err := database.Connect(path)
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		dbPath, dbConn = path, db
+		return nil
+	},
+}
+
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List schema migrations and whether they have been applied",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		statuses, err := database.Status(dbConn)
+		if err != nil {
+			return err
+		}
+
+		console.Println("\n" + strings.Repeat("=", 60))
+		color.Cyan("🗄️  SCHEMA MIGRATIONS")
+		console.Println(strings.Repeat("=", 60))
+		color.White("Database: %s", dbPath)
+		console.Println()
+
+		pending, unknown := 0, 0
+		for _, s := range statuses {
+			switch {
+			case s.Unknown:
+				unknown++
+				color.Red("⚠️  %3d %-28s applied %s by a newer DuoCLI", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04"))
+			case s.AppliedAt != nil:
+				color.Green("✅ %3d %-28s applied %s", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04"))
+			default:
+				pending++
+				color.Yellow("⏳ %3d %-28s pending", s.Version, s.Name)
+			}
+		}
+
+		console.Println()
+		if unknown > 0 {
+			color.Red("The database is newer than this DuoCLI, please upgrade")
+		} else if pending > 0 {
+			color.Yellow("%d pending, run 'duocli db migrate' to apply", pending)
+		} else {
+			color.Green("Schema is up to date")
+		}
+		console.Println(strings.Repeat("=", 60))
+		return nil
+	},
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending schema migrations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		applied, backup, err := database.Migrate(dbConn, dbPath)
+		for _, m := range applied {
+			color.Green("✅ Applied %d %s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			color.Green("✅ Schema is already at version %d", database.LatestVersion())
+			return nil
+		}
+		if backup != "" {
+			color.White("💾 Backup saved to %s", backup)
+		}
+		return nil
+	},
+}
+
+var dbRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Undo the most recent schema migrations",
+	Long: `Undo the most recently applied schema migrations, e.g. before going back to an
+older DuoCLI. Tables added by a migration are dropped along with their data,
+so the database is backed up first. The next command that opens the
+database applies the migrations again.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if rollbackSteps < 1 {
+			return fmt.Errorf("--steps must be at least 1")
+		}
+
+		targets, err := database.RollbackTargets(dbConn, rollbackSteps)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			color.Yellow("No migrations to roll back.")
+			return nil
+		}
+
+		color.Red("⚠️  WARNING: This will undo these migrations and drop the data they hold:")
+		for _, m := range targets {
+			color.White("   %d %s", m.Version, m.Name)
+		}
+		color.Yellow("A backup is made first. Are you sure? (type 'yes' to confirm)")
+		if !confirmed() {
+			color.Green("✅ Rollback cancelled.")
+			return nil
+		}
+
+		rolledBack, backup, err := database.Rollback(dbConn, dbPath, rollbackSteps)
+		for _, m := range rolledBack {
+			color.Green("↩️  Rolled back %d %s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+		color.White("💾 Backup saved to %s", backup)
+		return nil
+	},
+}
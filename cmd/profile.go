@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/models"
+	"duocli/internal/settings"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a new profile and switch to it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimSpace(args[0])
+		if _, err := findUser(name); err == nil {
+			return fmt.Errorf("a profile named %q already exists", name)
+		}
+
+		user, err := createUser(name)
+		if err != nil {
+			return fmt.Errorf("failed to create profile: %w", err)
+		}
+
+		color.Green("✅ Profile created! Welcome, %s!", user.Name)
+		return nil
+	},
+}
+
+var profileSwitchCmd = &cobra.Command{
+	Use:   "switch <name|id>",
+	Short: "Switch the active profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		user, err := findUser(args[0])
+		if err != nil {
+			return fmt.Errorf("profile %q not found", args[0])
+		}
+
+		if err := setActiveUser(user.ID); err != nil {
+			return fmt.Errorf("failed to switch profile: %w", err)
+		}
+
+		color.Green("✅ Switched to %s", user.Name)
+		return nil
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all profiles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		users, err := appStore.Users()
+		if err != nil {
+			return fmt.Errorf("failed to load profiles: %w", err)
+		}
+
+		if len(users) == 0 {
+			color.Yellow("No profiles yet. Create one with 'duocli profile create <name>'.")
+			return nil
+		}
+
+		active := settings.Get(appStore, 0, settings.ActiveProfile)
+
+		console.Println("\n" + strings.Repeat("=", 50))
+		color.Cyan("👥 PROFILES")
+		console.Println(strings.Repeat("=", 50))
+
+		for _, user := range users {
+			marker := "  "
+			if strconv.FormatUint(uint64(user.ID), 10) == active {
+				marker = "▶ "
+			}
+			color.White("%s%d. %s (Level %d, %d XP)", marker, user.ID, user.Name, user.Level, user.XP)
+		}
+
+		console.Println(strings.Repeat("=", 50))
+		return nil
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete <name|id>",
+	Short: "Delete a profile and all of its progress",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		user, err := findUser(args[0])
+		if err != nil {
+			return fmt.Errorf("profile %q not found", args[0])
+		}
+
+		color.Red("⚠️  WARNING: This will delete %s and ALL of their progress!", user.Name)
+		color.Yellow("This action cannot be undone. Are you sure? (type 'yes' to confirm)")
+
+		if !confirmed() {
+			color.Green("✅ Delete cancelled.")
+			return nil
+		}
+
+		if err := appStore.DeleteUser(user.ID); err != nil {
+			return fmt.Errorf("failed to delete profile: %w", err)
+		}
+
+		if settings.Get(appStore, 0, settings.ActiveProfile) == strconv.FormatUint(uint64(user.ID), 10) {
+			settings.Delete(appStore, 0, settings.ActiveProfile)
+		}
+
+		color.Green("✅ Profile %s deleted.", user.Name)
+		return nil
+	},
+}
+
+// findUser looks a profile up by numeric ID or case-insensitive name
+func findUser(ref string) (*models.User, error) {
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		if user, err := appStore.User(uint(id)); err == nil {
+			return &user, nil
+		}
+	}
+
+	user, err := appStore.UserByName(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// createUser adds a new profile and makes it the active one
+func createUser(name string) (*models.User, error) {
+	user := models.User{
+		Name:   name,
+		Level:  1,
+		XP:     0,
+		Streak: 0,
+	}
+
+	if err := appStore.CreateUser(&user); err != nil {
+		return nil, err
+	}
+
+	if err := setActiveUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// confirmed reads a line and reports whether the learner typed "yes"
+func confirmed() bool {
+	response, ok := console.ReadLine()
+	return ok && response == "yes"
+}
+
+func setActiveUser(userID uint) error {
+	return settings.Set(appStore, 0, settings.ActiveProfile, strconv.FormatUint(uint64(userID), 10))
+}
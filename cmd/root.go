@@ -1,15 +1,23 @@
 package cmd
 
 import (
-	"bufio"
+	"duocli/internal/config"
+	"duocli/internal/console"
 	"duocli/internal/database"
 	"duocli/internal/exercises"
 	"duocli/internal/models"
+	"duocli/internal/progress"
+	"duocli/internal/review"
+	"duocli/internal/settings"
+	"duocli/internal/store"
 	"duocli/internal/ui"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -17,11 +25,40 @@ import (
 
 var currentUser *models.User
 
+// appStore holds everything the commands read and write
+var appStore store.Store
+
+// appConfig is the configuration loaded from config.toml at startup
+var appConfig = config.Default()
+
+var (
+	dbFlag          string
+	userFlag        string
+	answersFileFlag string
+	transcriptFlag  string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "duocli",
 	Short: "Learn German in your CLI",
 	Long: `DuoCLI is a complete German language learning application for the command line.
 Learn vocabulary, complete lessons, and track your progress - all from your terminal!`,
+	// Execute prints errors once, to stderr, so they stay out of transcripts
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Arguments are fine by now; a setup error is no reason to print usage
+		cmd.SilenceUsage = true
+
+		if err := loadConfig(); err != nil {
+			return err
+		}
+		if err := setupScripting(os.Stdin, os.Stdout, os.Stderr); err != nil {
+			return err
+		}
+		exercises.Configure(appConfig)
+		ui.Configure(appConfig)
+		return openDatabase()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		runInteractiveMode()
 	},
@@ -29,7 +66,7 @@ Learn vocabulary, complete lessons, and track your progress - all from your term
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
@@ -40,7 +77,150 @@ func init() {
 	rootCmd.AddCommand(lessonsCmd)
 	rootCmd.AddCommand(vocabCmd)
 	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(reviewCmd)
 	rootCmd.AddCommand(resetCmd)
+	rootCmd.AddCommand(settingsCmd)
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbStatusCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbRollbackCmd)
+	dbRollbackCmd.Flags().IntVarP(&rollbackSteps, "steps", "n", 1, "number of migrations to undo")
+
+	rootCmd.PersistentFlags().StringVar(&dbFlag, "db", "", "database file (default $DUOCLI_DB or $XDG_DATA_HOME/duocli/duocli.db)")
+	rootCmd.PersistentFlags().StringVarP(&userFlag, "user", "u", "", "profile name or ID to use for this run")
+	rootCmd.PersistentFlags().StringVar(&answersFileFlag, "answers-file", "", "read answers line by line from a file ('-' for stdin) instead of prompting")
+	rootCmd.PersistentFlags().StringVar(&transcriptFlag, "transcript", "", "write a JSON-lines transcript of the session to a file ('-' for stdout)")
+
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileSwitchCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+
+	rootCmd.AddCommand(contentCmd)
+	contentCmd.AddCommand(contentImportCmd)
+	contentCmd.AddCommand(contentLintCmd)
+	contentImportCmd.Flags().BoolVarP(&contentForce, "force", "f", false, "re-import even if the installed version is the same or newer")
+	contentLintCmd.Flags().BoolVar(&contentLintInstalled, "installed", false, "lint the content installed in the database")
+
+	vocabCmd.Flags().BoolVar(&vocabWeak, "weak", false, "only show practised words you are weak on, weakest first")
+	vocabCmd.Flags().BoolVar(&vocabMastered, "mastered", false, "only show words you have mastered, strongest first")
+
+	reviewCmd.Flags().IntVarP(&reviewLimit, "limit", "n", 20, "maximum number of items to review")
+
+	rootCmd.AddCommand(matchCmd)
+
+	rootCmd.AddCommand(conjugateCmd)
+
+	rootCmd.AddCommand(declineCmd)
+	declineCmd.Flags().StringVar(&declinePlural, "plural", "", "plural form of the noun, e.g. Hunde")
+	declineCmd.Flags().StringVarP(&declineAdjective, "adjective", "a", "", "adjective to decline with the noun, e.g. klein")
+	conjugateCmd.Flags().StringVarP(&conjugatePrefix, "prefix", "p", "", "separable prefix of the verb, e.g. auf for aufmachen")
+
+	rootCmd.AddCommand(practiceCmd)
+	practiceCmd.AddCommand(practiceVocabCmd)
+	practiceVocabCmd.Flags().IntVarP(&practiceCount, "count", "n", 10, "number of words to practise")
+	practiceVocabCmd.Flags().StringVarP(&practiceMode, "mode", "m", exercises.PracticeMixed, "de-en, en-de, cloze or mixed")
+
+	rootCmd.AddCommand(drillCmd)
+	drillCmd.Flags().IntVarP(&drillOpts.Count, "count", "n", 20, "maximum number of questions")
+	drillCmd.Flags().DurationVarP(&drillOpts.QuestionTime, "time", "t", 10*time.Second, "time allowed per question")
+	drillCmd.Flags().DurationVarP(&drillOpts.SessionTime, "duration", "d", 2*time.Minute, "time allowed for the whole drill")
+
+	drillCmd.AddCommand(drillGenderCmd)
+	drillGenderCmd.Flags().IntVarP(&genderCount, "count", "n", 10, "number of nouns to ask")
+	drillGenderCmd.Flags().BoolVar(&genderStatsOnly, "stats", false, "only show gender statistics")
+}
+
+// setupScripting switches the console to a script of answers and turns on
+// the session transcript when the matching flags are set. With the
+// transcript on stdout, everything meant for humans moves to stderr.
+func setupScripting(stdin io.Reader, stdout, stderr io.Writer) error {
+	if answersFileFlag == "" && transcriptFlag == "" {
+		return nil
+	}
+
+	in := stdin
+	out := console.Writer()
+
+	if answersFileFlag != "" && answersFileFlag != "-" {
+		file, err := os.Open(answersFileFlag)
+		if err != nil {
+			return fmt.Errorf("failed to open answers file: %w", err)
+		}
+		in = file
+	}
+
+	if answersFileFlag != "" {
+		// Nobody is reading along, so don't wait between answers
+		appConfig.Pause = 0
+	}
+
+	switch transcriptFlag {
+	case "":
+	case "-":
+		exercises.SetTranscript(stdout)
+		out = stderr
+	default:
+		file, err := os.Create(transcriptFlag)
+		if err != nil {
+			return fmt.Errorf("failed to create transcript: %w", err)
+		}
+		exercises.SetTranscript(file)
+	}
+
+	console.Use(console.New(in, out))
+	return nil
+}
+
+// openDatabase opens the database at the location picked by database.Path.
+// A database left in the current directory by older versions is offered for
+// moving there the first time, unless the location was chosen explicitly.
+func openDatabase() error {
+	path, explicit, err := database.Path(dbFlag)
+	if err != nil {
+		return err
+	}
+
+	// Declined moves are remembered next to config.toml so the offer is made once
+	configPath, err := config.Path()
+	if err != nil {
+		return err
+	}
+	marker := filepath.Join(filepath.Dir(configPath), "legacy-db-declined")
+	if !explicit && database.LegacyDatabase(path) && !database.LegacyDeclined(marker) {
+		if answersFileFlag != "" {
+			// Don't spend a scripted answer on the question
+			color.Yellow("⚠️  Found %s in this directory; run without --answers-file to move it to %s", database.LegacyPath, path)
+		} else {
+			color.Yellow("📦 Found %s from an older version of DuoCLI.", database.LegacyPath)
+			console.Printf("Move it to %s so it is used from every directory? [Y/n] ", path)
+			answer, ok := console.ReadLine()
+			answer = strings.ToLower(answer)
+			if ok && (answer == "" || answer == "y" || answer == "yes") {
+				if err := database.MoveLegacy(path); err != nil {
+					return fmt.Errorf("failed to move %s: %w", database.LegacyPath, err)
+				}
+				color.Green("✅ Moved your progress to %s", path)
+			} else {
+				if err := database.DeclineLegacy(marker); err != nil {
+					return fmt.Errorf("failed to remember your answer: %w", err)
+				}
+				color.Yellow("Keeping it where it is; use --db %s to open it. You won't be asked again.", database.LegacyPath)
+			}
+		}
+	}
+
+	db, err := database.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	appStore = store.NewGorm(db)
+	return nil
 }
 
 func runInteractiveMode() {
@@ -49,63 +229,68 @@ func runInteractiveMode() {
 	// Ensure user exists
 	ensureUser()
 	
-	scanner := bufio.NewScanner(os.Stdin)
-	
 	for {
 		showMainMenu()
-		fmt.Print("Choose an option: ")
+		console.Print("Choose an option: ")
 		
-		if !scanner.Scan() {
+		choice, ok := console.ReadLine()
+		if !ok {
 			break
 		}
 		
-		choice := strings.TrimSpace(scanner.Text())
-		
 		switch choice {
 		case "1":
 			startLearning()
 		case "2":
-			ui.ShowUserProfile(currentUser.ID)
+			ui.ShowUserProfile(appStore, currentUser.ID)
 		case "3":
-			ui.ShowLessons()
+			ui.ShowLessons(appStore, currentUser.ID)
 		case "4":
 			showVocabMenu()
 		case "5":
-			ui.ShowStats(currentUser.ID)
+			ui.ShowStats(appStore, currentUser.ID)
 		case "6":
+			if err := exercises.StartReview(appStore, currentUser.ID, 20); err != nil {
+				color.Red("❌ Error starting review: %v", err)
+			}
+		case "7":
 			color.Cyan("👋 Auf Wiedersehen! (Goodbye!)")
 			return
 		default:
 			color.Red("❌ Invalid option. Please try again.")
 		}
 		
-		fmt.Print("\nPress Enter to continue...")
-		scanner.Scan()
+		console.Print("\nPress Enter to continue...")
+		console.ReadLine()
 	}
 }
 
 func showMainMenu() {
-	fmt.Println("\n" + strings.Repeat("=", 50))
+	console.Println("\n" + strings.Repeat("=", 50))
 	color.Cyan("🏠 MAIN MENU")
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 	
 	color.White("1. 🎓 Start Learning")
 	color.White("2. 👤 Profile")
 	color.White("3. 📚 View Lessons")
 	color.White("4. 📖 Vocabulary")
 	color.White("5. 📊 Statistics")
-	color.White("6. 🚪 Exit")
+	color.White("6. 🔁 Review (%d due)", review.DueCount(appStore, currentUser.ID))
+	color.White("7. 🚪 Exit")
 	
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 }
 
 func startLearning() {
-	var lessons []models.Lesson
-	database.DB.Order("\"order\"").Find(&lessons)
+	lessons, err := ui.CourseLessons(appStore)
+	if err != nil {
+		color.Red("❌ Failed to load lessons: %v", err)
+		return
+	}
 	
-	fmt.Println("\n" + strings.Repeat("=", 40))
+	console.Println("\n" + strings.Repeat("=", 40))
 	color.Cyan("🎓 SELECT A LESSON")
-	fmt.Println(strings.Repeat("=", 40))
+	console.Println(strings.Repeat("=", 40))
 	
 	availableLessons := []models.Lesson{}
 	
@@ -113,10 +298,10 @@ func startLearning() {
 		status := "🔒 Locked"
 		available := false
 		
-		if lesson.IsCompleted {
+		if progress.IsCompleted(appStore, currentUser.ID, lesson.ID) {
 			status = "✅ Completed"
 			available = true
-		} else if isLessonUnlocked(lesson) {
+		} else if progress.IsUnlocked(appStore, currentUser.ID, lesson) {
 			status = "🔓 Available"
 			available = true
 		}
@@ -134,10 +319,8 @@ func startLearning() {
 		return
 	}
 	
-	fmt.Print("\nSelect lesson number (0 to cancel): ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	choice := strings.TrimSpace(scanner.Text())
+	console.Print("\nSelect lesson number (0 to cancel): ")
+	choice, _ := console.ReadLine()
 	
 	if choice == "0" {
 		return
@@ -152,22 +335,22 @@ func startLearning() {
 	selectedLesson := lessons[lessonNum-1]
 	
 	// Check if lesson is unlocked
-	if !selectedLesson.IsCompleted && !isLessonUnlocked(selectedLesson) {
+	if !progress.IsCompleted(appStore, currentUser.ID, selectedLesson.ID) && !progress.IsUnlocked(appStore, currentUser.ID, selectedLesson) {
 		color.Red("🔒 This lesson is locked! Complete previous lessons first.")
 		return
 	}
 	
 	// Start the lesson
-	err = exercises.StartLesson(currentUser.ID, selectedLesson.ID)
+	err = exercises.StartLesson(appStore, currentUser.ID, selectedLesson.ID)
 	if err != nil {
 		color.Red("❌ Error starting lesson: %v", err)
 	}
 }
 
 func showVocabMenu() {
-	fmt.Println("\n" + strings.Repeat("=", 40))
+	console.Println("\n" + strings.Repeat("=", 40))
 	color.Cyan("📖 VOCABULARY MENU")
-	fmt.Println(strings.Repeat("=", 40))
+	console.Println(strings.Repeat("=", 40))
 	
 	color.White("1. 👋 Greetings")
 	color.White("2. 👁️  Pronouns")
@@ -175,13 +358,12 @@ func showVocabMenu() {
 	color.White("4. 🏠 Objects")
 	color.White("5. 🚗 Transport")
 	color.White("6. 🔧 Verbs")
-	color.White("7. 📚 All Vocabulary")
+	color.White("7. 👪 Family")
+	color.White("8. 📚 All Vocabulary")
 	color.White("0. 🔙 Back to Main Menu")
 	
-	fmt.Print("\nChoose category: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	choice := strings.TrimSpace(scanner.Text())
+	console.Print("\nChoose category: ")
+	choice, _ := console.ReadLine()
 	
 	categories := map[string]string{
 		"1": "greetings",
@@ -190,7 +372,8 @@ func showVocabMenu() {
 		"4": "objects",
 		"5": "transport",
 		"6": "verbs",
-		"7": "",
+		"7": "family",
+		"8": "",
 	}
 	
 	if choice == "0" {
@@ -198,53 +381,64 @@ func showVocabMenu() {
 	}
 	
 	if category, exists := categories[choice]; exists {
-		ui.ShowVocabulary(category)
+		ui.ShowVocabulary(appStore, currentUser.ID, category, ui.AllWords)
 	} else {
 		color.Red("❌ Invalid option!")
 	}
 }
 
 func ensureUser() {
-	var user models.User
-	err := database.DB.First(&user).Error
-	
+	user, err := resolveUser()
 	if err != nil {
+		color.Red("❌ %v", err)
+		os.Exit(1)
+	}
+
+	if user == nil {
 		// Create new user
 		color.Yellow("👋 Welcome to DuoCLI! Let's set up your profile.")
-		fmt.Print("Enter your name: ")
-		
-		scanner := bufio.NewScanner(os.Stdin)
-		scanner.Scan()
-		name := strings.TrimSpace(scanner.Text())
-		
+		console.Print("Enter your name: ")
+		name, _ := console.ReadLine()
+
 		if name == "" {
 			name = "Learner"
 		}
-		
-		user = models.User{
-			Name:     name,
-			Level:    1,
-			XP:       0,
-			Streak:   0,
+
+		user, err = createUser(name)
+		if err != nil {
+			color.Red("❌ Failed to create profile: %v", err)
+			os.Exit(1)
 		}
-		
-		database.DB.Create(&user)
 		color.Green("✅ Profile created! Welcome, %s!", user.Name)
 	}
-	
-	currentUser = &user
+
+	currentUser = user
 }
 
-func isLessonUnlocked(lesson models.Lesson) bool {
-	if lesson.Order == 1 {
-		return true
+// resolveUser picks the profile to run as: the --user flag wins, then the
+// remembered active profile, then the first profile on record. It returns
+// nil when no profile exists yet.
+func resolveUser() (*models.User, error) {
+	if userFlag != "" {
+		user, err := findUser(userFlag)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q not found", userFlag)
+		}
+		return user, nil
+	}
+
+	if active := settings.Get(appStore, 0, settings.ActiveProfile); active != "" {
+		if user, err := findUser(active); err == nil {
+			return user, nil
+		}
 	}
-	
-	var prevLesson models.Lesson
-	err := database.DB.Where("\"order\" = ?", lesson.Order-1).First(&prevLesson).Error
-	if err != nil {
-		return false
+
+	users, err := appStore.Users()
+	if err != nil || len(users) == 0 {
+		return nil, nil
 	}
-	
-	return prevLesson.IsCompleted
-}
\ No newline at end of file
+	user := users[0]
+
+	setActiveUser(user.ID)
+	return &user, nil
+}
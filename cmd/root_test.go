@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"duocli/internal/config"
+	"duocli/internal/console"
+	"duocli/internal/exercises"
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetupScriptingTranscriptOnStdout(t *testing.T) {
+	answersFileFlag, transcriptFlag, appConfig = "-", "-", config.Default()
+	t.Cleanup(func() {
+		answersFileFlag, transcriptFlag, appConfig = "", "", config.Default()
+		exercises.SetTranscript(nil)
+		exercises.Configure(config.Default())
+		console.Use(console.New(strings.NewReader(""), io.Discard))
+	})
+
+	var stdout, stderr bytes.Buffer
+	if err := setupScripting(strings.NewReader("hello\n"), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if appConfig.Pause != 0 {
+		t.Errorf("pause = %s with an answers file, want none", appConfig.Pause)
+	}
+	exercises.Configure(appConfig)
+
+	s := store.NewMemory()
+	user := models.User{Name: "tester"}
+	lesson := models.Lesson{Title: "Basics", Order: 1}
+	if err := s.CreateUser(&user); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveLesson(&lesson); err != nil {
+		t.Fatal(err)
+	}
+	exercise := models.Exercise{LessonID: lesson.ID, Order: 1, Type: "translation", Question: "Hallo", Answer: "Hello"}
+	if err := s.SaveExercise(&exercise); err != nil {
+		t.Fatal(err)
+	}
+	if err := exercises.StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stdout holds nothing but transcript lines
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	for _, line := range lines {
+		var event exercises.TranscriptEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Event == "" {
+			t.Errorf("stdout line %q is not a transcript event", line)
+		}
+	}
+	if len(lines) != 3 {
+		t.Errorf("got %d transcript lines, want lesson_start, answer and lesson_end", len(lines))
+	}
+	if !strings.Contains(stderr.String(), "Starting Lesson: Basics") {
+		t.Errorf("stderr = %q, want the lesson's human output", stderr.String())
+	}
+}
+
+func TestConfigSetRejectsInvalidValue(t *testing.T) {
+	t.Setenv("DUOCLI_CONFIG", t.TempDir()+"/config.toml")
+
+	if err := configSetCmd.RunE(configSetCmd, []string{"xp_per_answer", "lots"}); err == nil {
+		t.Error("config set xp_per_answer lots succeeded, want an error")
+	}
+	if err := configSetCmd.RunE(configSetCmd, []string{"no_such_key", "1"}); err == nil {
+		t.Error("config set no_such_key succeeded, want an error")
+	}
+}
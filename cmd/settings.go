@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/settings"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var settingsCmd = &cobra.Command{
+	Use:   "settings [key] [value]",
+	Short: "Show or change your profile settings",
+	Long: `Without arguments, list the settings for the active profile. With a key and
+a value, change that setting. Available settings:
+
+  hearts         on|off: lose a heart for every mistake; a lesson ends when they run out
+  choices        2-6: number of options in multiple choice questions
+  choice_labels  numbers|letters: how multiple choice options are labelled`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ensureUser()
+
+		if len(args) == 0 {
+			showSettings()
+			return nil
+		}
+
+		key := args[0]
+		if _, known := settings.UserDefaults[key]; !known {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+
+		if len(args) == 1 {
+			color.White("%s = %s", key, settings.GetOrDefault(appStore, currentUser.ID, key))
+			return nil
+		}
+
+		value := strings.ToLower(args[1])
+		if err := settings.Validate(key, value); err != nil {
+			return err
+		}
+
+		if err := settings.Set(appStore, currentUser.ID, key, value); err != nil {
+			return fmt.Errorf("failed to save setting: %w", err)
+		}
+		color.Green("✅ %s is now %s", key, value)
+		return nil
+	},
+}
+
+func showSettings() {
+	console.Println("\n" + strings.Repeat("=", 40))
+	color.Cyan("⚙️  SETTINGS - %s", currentUser.Name)
+	console.Println(strings.Repeat("=", 40))
+
+	keys := make([]string, 0, len(settings.UserDefaults))
+	for key := range settings.UserDefaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		color.White("%-14s %s", key, settings.GetOrDefault(appStore, currentUser.ID, key))
+	}
+
+	console.Println(strings.Repeat("=", 40))
+}
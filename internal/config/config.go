@@ -0,0 +1,319 @@
+// Package config reads and writes the user's config.toml, which holds
+// app-wide preferences such as pass marks, pacing and the colour theme.
+// Per-profile settings live in the database, see package settings.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the user's configuration. Zero values are not meaningful; start
+// from Default.
+type Config struct {
+	PassMark    float64       // First-try accuracy (%) that completes a lesson
+	BonusMark   float64       // Accuracy that earns the lesson's bonus XP
+	PerfectMark float64       // Accuracy celebrated as a perfect score
+	XPPerAnswer int           // XP for a correct answer; drills pay 40% of it
+	Course      string        // Language of the lessons to show
+	Pause       time.Duration // Delay after each answer so feedback can be read
+	ShowHints   bool          // Show exercise hints before answering
+	Theme       string        // default or mono
+	DailyGoal   int           // Lesson exercises to answer each day
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		PassMark:    70,
+		BonusMark:   80,
+		PerfectMark: 90,
+		XPPerAnswer: 5,
+		Course:      "german",
+		Pause:       time.Second,
+		ShowHints:   true,
+		Theme:       "default",
+		DailyGoal:   10,
+	}
+}
+
+// Themes lists the colour themes
+var Themes = []string{"default", "mono"}
+
+// Setting describes one key of the config file
+type Setting struct {
+	Key         string // table.key, e.g. lessons.pass_mark
+	Description string
+	get         func(c *Config) string
+	set         func(c *Config, value string) error
+	quoted      bool // Written as a TOML string
+}
+
+// Settings lists every key of the config file in the order it is written
+var Settings = []Setting{
+	{
+		Key:         "lessons.pass_mark",
+		Description: "first-try accuracy (%) needed to complete a lesson",
+		get:         func(c *Config) string { return formatFloat(c.PassMark) },
+		set:         func(c *Config, v string) error { return setPercent(&c.PassMark, v) },
+	},
+	{
+		Key:         "lessons.bonus_mark",
+		Description: "accuracy (%) that earns the lesson's bonus XP",
+		get:         func(c *Config) string { return formatFloat(c.BonusMark) },
+		set:         func(c *Config, v string) error { return setPercent(&c.BonusMark, v) },
+	},
+	{
+		Key:         "lessons.perfect_mark",
+		Description: "accuracy (%) celebrated as a perfect score",
+		get:         func(c *Config) string { return formatFloat(c.PerfectMark) },
+		set:         func(c *Config, v string) error { return setPercent(&c.PerfectMark, v) },
+	},
+	{
+		Key:         "lessons.xp_per_answer",
+		Description: "XP for a correct answer (drill answers earn 40% of it)",
+		get:         func(c *Config) string { return strconv.Itoa(c.XPPerAnswer) },
+		set:         func(c *Config, v string) error { return setInt(&c.XPPerAnswer, v, 1, 100) },
+	},
+	{
+		Key:         "lessons.course",
+		Description: "language of the lessons to show, e.g. german",
+		get:         func(c *Config) string { return c.Course },
+		set: func(c *Config, v string) error {
+			c.Course = strings.ToLower(strings.TrimSpace(v))
+			return nil
+		},
+		quoted: true,
+	},
+	{
+		Key:         "display.pause",
+		Description: "delay after each answer, e.g. 1s, 500ms or 0",
+		get:         func(c *Config) string { return c.Pause.String() },
+		set: func(c *Config, v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				return fmt.Errorf("must be a duration such as 1s or 500ms")
+			}
+			c.Pause = d
+			return nil
+		},
+		quoted: true,
+	},
+	{
+		Key:         "display.hints",
+		Description: "show exercise hints (true or false)",
+		get:         func(c *Config) string { return strconv.FormatBool(c.ShowHints) },
+		set: func(c *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("must be true or false")
+			}
+			c.ShowHints = b
+			return nil
+		},
+	},
+	{
+		Key:         "display.theme",
+		Description: "colour theme: " + strings.Join(Themes, " or "),
+		get:         func(c *Config) string { return c.Theme },
+		set: func(c *Config, v string) error {
+			for _, theme := range Themes {
+				if v == theme {
+					c.Theme = v
+					return nil
+				}
+			}
+			return fmt.Errorf("must be one of: %s", strings.Join(Themes, ", "))
+		},
+		quoted: true,
+	},
+	{
+		Key:         "goals.daily",
+		Description: "lesson exercises to answer each day",
+		get:         func(c *Config) string { return strconv.Itoa(c.DailyGoal) },
+		set:         func(c *Config, v string) error { return setInt(&c.DailyGoal, v, 1, 1000) },
+	},
+}
+
+// Lookup returns the setting for key
+func Lookup(key string) (Setting, bool) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// Get returns the value of a setting as it would be written to the file
+func (c *Config) Get(key string) (string, error) {
+	s, ok := Lookup(key)
+	if !ok {
+		return "", fmt.Errorf("unknown setting %q", key)
+	}
+	return s.get(c), nil
+}
+
+// Set changes a setting, checking the value and that the pass marks still
+// go up from pass to bonus to perfect
+func (c *Config) Set(key, value string) error {
+	s, ok := Lookup(key)
+	if !ok {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if err := s.set(c, value); err != nil {
+		return fmt.Errorf("%s %w", key, err)
+	}
+	return c.check()
+}
+
+func (c *Config) check() error {
+	if c.PassMark > c.BonusMark || c.BonusMark > c.PerfectMark {
+		return fmt.Errorf("marks must not decrease from pass_mark (%s) to bonus_mark (%s) to perfect_mark (%s)",
+			formatFloat(c.PassMark), formatFloat(c.BonusMark), formatFloat(c.PerfectMark))
+	}
+	return nil
+}
+
+// Path returns where the config file lives: $DUOCLI_CONFIG, or
+// $XDG_CONFIG_HOME/duocli/config.toml with XDG_CONFIG_HOME defaulting to
+// ~/.config
+func Path() (string, error) {
+	if env := os.Getenv("DUOCLI_CONFIG"); env != "" {
+		return env, nil
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot find the home directory: %w", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "duocli", "config.toml"), nil
+}
+
+// Load reads the config file at path on top of the defaults. A missing file
+// yields the defaults.
+func Load(path string) (*Config, error) {
+	return load(path, false)
+}
+
+// LoadLenient reads the config file at path like Load, but skips what it
+// can't use instead of failing, so that a broken file can still be repaired.
+// The error, if any, lists what was skipped; the returned config is never nil.
+func LoadLenient(path string) (*Config, error) {
+	return load(path, true)
+}
+
+func load(path string, lenient bool) (*Config, error) {
+	c := Default()
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		if lenient {
+			return c, err
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	values, err := parseTOML(file)
+	if err != nil {
+		if lenient {
+			return c, fmt.Errorf("%s: %w", path, err)
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var skipped []error
+	for _, key := range keys {
+		var err error
+		if s, ok := Lookup(key); !ok {
+			err = fmt.Errorf("%s: unknown setting %q", path, key)
+		} else if serr := s.set(c, fmt.Sprint(values[key])); serr != nil {
+			err = fmt.Errorf("%s: %s %w", path, key, serr)
+		}
+		if err == nil {
+			continue
+		}
+		if !lenient {
+			return nil, err
+		}
+		skipped = append(skipped, err)
+	}
+
+	// A lenient load keeps values that only clash with each other, so that
+	// setting one of them can resolve the clash
+	if err := c.check(); err != nil {
+		if !lenient {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		skipped = append(skipped, fmt.Errorf("%s: %w", path, err))
+	}
+	return c, errors.Join(skipped...)
+}
+
+// Save writes every setting to the config file at path, creating its
+// directory. Comments in an existing file are not kept.
+func (c *Config) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("# DuoCLI configuration, see `duocli config list`\n")
+	table := ""
+	for _, s := range Settings {
+		dot := strings.Index(s.Key, ".")
+		if s.Key[:dot] != table {
+			table = s.Key[:dot]
+			fmt.Fprintf(&b, "\n[%s]\n", table)
+		}
+		value := s.get(c)
+		if s.quoted {
+			value = quote(value)
+		}
+		fmt.Fprintf(&b, "# %s\n%s = %s\n", s.Description, s.Key[dot+1:], value)
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+func setPercent(field *float64, value string) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || f > 100 {
+		return fmt.Errorf("must be a percentage from 0 to 100")
+	}
+	*field = f
+	return nil
+}
+
+func setInt(field *int, value string, min, max int) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return fmt.Errorf("must be a whole number from %d to %d", min, max)
+	}
+	*field = n
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+[lessons]
+pass_mark = 60
+course = "Spanish"
+
+[display]
+pause = "0"
+hints = false
+theme = "mono"
+`)
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Default()
+	want.PassMark = 60
+	want.Course = "spanish"
+	want.Pause = 0
+	want.ShowHints = false
+	want.Theme = "mono"
+	if *c != *want {
+		t.Errorf("Load = %+v, want %+v", *c, *want)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), "none.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *c != *Default() {
+		t.Errorf("Load = %+v, want the defaults", *c)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct{ content, want string }{
+		{"[lessons]\npass = 70\n", `unknown setting "lessons.pass"`},
+		{"[lessons]\npass_mark = 120\n", "lessons.pass_mark must be a percentage"},
+		{"[lessons]\npass_mark = 90\nbonus_mark = 80\n", "marks must not decrease"},
+		{"[display]\npause = \"soon\"\n", "display.pause must be a duration"},
+		{"[display]\ntheme = \"neon\"\n", "display.theme must be one of"},
+		{"[goals]\ndaily = 0\n", "goals.daily must be a whole number"},
+		{"[goals\n", "invalid table header"},
+	}
+
+	for _, tt := range tests {
+		_, err := Load(writeConfig(t, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Load(%q) error = %v, want %q", tt.content, err, tt.want)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	c := Default()
+	c.Course = `odd "course"`
+	c.Pause = 250 * time.Millisecond
+	c.DailyGoal = 25
+
+	path := filepath.Join(t.TempDir(), "duocli", "config.toml")
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *c {
+		t.Errorf("Load after Save = %+v, want %+v", *loaded, *c)
+	}
+}
+
+func TestLoadLenient(t *testing.T) {
+	path := writeConfig(t, `
+[lessons]
+pass_mark = 90
+bonus_mark = 80
+pass = 70
+
+[display]
+theme = "mono"
+pause = "soon"
+`)
+
+	c, err := LoadLenient(path)
+	if err == nil {
+		t.Fatal("LoadLenient returned no error for a broken file")
+	}
+	for _, want := range []string{`unknown setting "lessons.pass"`, "display.pause must be a duration", "marks must not decrease"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadLenient error = %v, want %q", err, want)
+		}
+	}
+
+	want := Default()
+	want.PassMark = 90
+	want.BonusMark = 80
+	want.Theme = "mono"
+	if *c != *want {
+		t.Errorf("LoadLenient = %+v, want %+v", *c, *want)
+	}
+
+	// Setting one of the clashing marks repairs the file
+	if err := c.Set("lessons.bonus_mark", "90"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Errorf("Load after repair: %v", err)
+	}
+
+	c, err = LoadLenient(writeConfig(t, "[goals\n"))
+	if err == nil || *c != *Default() {
+		t.Errorf("LoadLenient of unparseable file = %+v, %v, want the defaults and an error", c, err)
+	}
+}
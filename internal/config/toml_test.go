@@ -0,0 +1,55 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]interface{}
+	}{
+		{"empty", "# only a comment\n\n", map[string]interface{}{}},
+		{"tables", "top = 1\n[lessons]\npass_mark = 75.5\n[display]\nhints = false\n",
+			map[string]interface{}{"top": int64(1), "lessons.pass_mark": 75.5, "display.hints": false}},
+		{"strings", "a = \"say \\\"hi\\\"\\n\"\nb = 'C:\\path'\n",
+			map[string]interface{}{"a": "say \"hi\"\n", "b": `C:\path`}},
+		{"comments", "theme = \"mono # not a comment\" # a comment\nn = 1_000\n",
+			map[string]interface{}{"theme": "mono # not a comment", "n": int64(1000)}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTOML(strings.NewReader(tt.input))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{"[lessons\n", "line 1: invalid table header"},
+		{"[[lessons]]\n", "line 1: invalid table header"},
+		{"just words\n", "line 1: expected key = value"},
+		{"a = 1\na = 2\n", "line 2: a is set twice"},
+		{"a =\n", "missing value"},
+		{"a = \"open\n", "unterminated string"},
+		{"a = \"bad \\q\"\n", "unsupported escape"},
+		{"a = 2024-01-01\n", "unsupported value"},
+		{"bad key = 1\n", "invalid key"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseTOML(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseTOML(%q) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
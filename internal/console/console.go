@@ -0,0 +1,140 @@
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Line is a line of input along with when it arrived
+type Line struct {
+	Text string
+	At   time.Time
+	EOF  bool // Input has ended; no line came and none will
+}
+
+// Console is where prompts are written and answers are read from. All
+// interactive code goes through the current console so sessions can run
+// against a script instead of a terminal.
+type Console struct {
+	in      *bufio.Reader
+	out     io.Writer
+	pending chan Line // A read that is still in flight
+}
+
+// New creates a console reading from in and writing to out
+func New(in io.Reader, out io.Writer) *Console {
+	return &Console{in: bufio.NewReader(in), out: out}
+}
+
+var current = New(os.Stdin, color.Output)
+
+// Use makes c the console for all prompts and output, including coloured
+// messages
+func Use(c *Console) {
+	current = c
+	color.Output = c.out
+}
+
+// Writer returns the current output writer
+func Writer() io.Writer {
+	return current.out
+}
+
+func Print(args ...interface{}) {
+	fmt.Fprint(current.out, args...)
+}
+
+func Println(args ...interface{}) {
+	fmt.Fprintln(current.out, args...)
+}
+
+func Printf(format string, args ...interface{}) {
+	fmt.Fprintf(current.out, format, args...)
+}
+
+// ReadLine blocks until a line of input is available. It returns false once
+// input is exhausted.
+func ReadLine() (string, bool) {
+	line, ok := ReadLineTimeout(0)
+	return line.Text, ok
+}
+
+// ReadLineTimeout waits up to timeout for a line of input; zero waits
+// forever. It returns false when no line came: check the line's EOF to tell
+// the end of input from a timeout or an interrupt. On timeout the read stays
+// in flight and the next call picks it up, so callers should compare the
+// line's arrival time against when they asked the question.
+func ReadLineTimeout(timeout time.Duration) (Line, bool) {
+	c := current
+	if c.pending == nil {
+		c.pending = make(chan Line, 1)
+		go func(ch chan Line) {
+			text, err := c.in.ReadString('\n')
+			text = strings.TrimSpace(text)
+			ch <- Line{Text: text, At: time.Now(), EOF: err != nil && text == ""}
+		}(c.pending)
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case line := <-c.pending:
+		c.pending = nil
+		if line.Text == InterruptLine && interrupts != nil {
+			interrupted = true
+			return Line{}, false
+		}
+		return line, !line.EOF
+	case <-expired:
+		return Line{}, false
+	case <-interrupts:
+		interrupted = true
+		return Line{}, false
+	}
+}
+
+// Pending reports whether a timed-out read is still waiting for input
+func Pending() bool {
+	return current.pending != nil
+}
+
+// InterruptLine is a line of scripted input that works like pressing Ctrl-C
+// while interrupts are caught, so scripts and tests can interrupt a session
+const InterruptLine = "\x03"
+
+var (
+	interrupts  chan os.Signal // Nil unless Ctrl-C is being caught
+	interrupted bool
+)
+
+// CatchInterrupts makes Ctrl-C cut the current read short instead of ending
+// the process, until stop is called. Like a timeout, the read stays in
+// flight for the next call. Check Interrupted after reading.
+func CatchInterrupts() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt)
+	interrupts, interrupted = ch, false
+	return func() {
+		signal.Stop(ch)
+		interrupts, interrupted = nil, false
+	}
+}
+
+// Interrupted reports whether Ctrl-C cut a read short since the last call
+func Interrupted() bool {
+	was := interrupted
+	interrupted = false
+	return was
+}
@@ -0,0 +1,77 @@
+package console
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadLine(t *testing.T) {
+	Use(New(strings.NewReader("hallo\n  guten Tag  \n\ntschüss"), io.Discard))
+
+	for _, want := range []string{"hallo", "guten Tag", "", "tschüss"} {
+		if got, ok := ReadLine(); got != want || !ok {
+			t.Errorf("ReadLine = %q, %v, want %q, true", got, ok, want)
+		}
+	}
+
+	// The end of input is reported the same way every time it is asked
+	for i := 0; i < 2; i++ {
+		line, ok := ReadLineTimeout(time.Second)
+		if ok || !line.EOF {
+			t.Errorf("ReadLineTimeout at the end = %+v, %v, want EOF", line, ok)
+		}
+	}
+}
+
+func TestReadLineTimeout(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	Use(New(r, io.Discard))
+
+	line, ok := ReadLineTimeout(10 * time.Millisecond)
+	if ok || line.EOF {
+		t.Fatalf("ReadLineTimeout with no input = %+v, %v, want a timeout", line, ok)
+	}
+	if !Pending() {
+		t.Fatal("Pending = false after a timeout, want the read kept in flight")
+	}
+
+	// The read that timed out picks up the line typed afterwards
+	asked := time.Now()
+	go io.WriteString(w, "spät\n")
+	line, ok = ReadLineTimeout(time.Second)
+	if !ok || line.Text != "spät" || line.At.Before(asked) {
+		t.Errorf("ReadLineTimeout = %+v, %v, want spät after %s", line, ok, asked)
+	}
+	if Pending() {
+		t.Error("Pending = true after the line arrived")
+	}
+}
+
+func TestInterruptLine(t *testing.T) {
+	script := "eins\n" + InterruptLine + "\nzwei\n"
+
+	// Without interrupts being caught the line is just text
+	Use(New(strings.NewReader(script), io.Discard))
+	ReadLine()
+	if got, ok := ReadLine(); got != InterruptLine || !ok {
+		t.Errorf("ReadLine = %q, %v, want the interrupt line as text", got, ok)
+	}
+
+	Use(New(strings.NewReader(script), io.Discard))
+	stop := CatchInterrupts()
+	defer stop()
+
+	ReadLine()
+	if _, ok := ReadLine(); ok || !Interrupted() {
+		t.Error("the interrupt line did not interrupt the read")
+	}
+	if Interrupted() {
+		t.Error("Interrupted = true twice for one interrupt")
+	}
+	if got, ok := ReadLine(); got != "zwei" || !ok {
+		t.Errorf("ReadLine after the interrupt = %q, %v, want zwei", got, ok)
+	}
+}
@@ -0,0 +1,399 @@
+package content
+
+import (
+	"duocli/internal/grammar"
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Report summarises what an import changed
+type Report struct {
+	Pack             string
+	Version          int
+	Skipped          bool // Installed version was already up to date
+	LessonsCreated   int
+	LessonsUpdated   int
+	LessonsRemoved   int
+	ExercisesCreated int
+	ExercisesUpdated int
+	ExercisesRemoved int
+	VocabCreated     int
+	VocabUpdated     int
+}
+
+// InstalledVersion returns the version of the named pack last imported into
+// s, or 0 if it has never been imported
+func InstalledVersion(s store.SettingStore, name string) int {
+	value, err := s.Setting(0, versionKey(name))
+	if err != nil {
+		return 0
+	}
+	version, _ := strconv.Atoi(value)
+	return version
+}
+
+// Import upserts a pack's lessons, exercises and vocabulary by their stable
+// keys. Rows are updated in place so learner progress that points at them
+// survives. Keyed lessons and exercises that an earlier import of the pack
+// installed but this version no longer has are removed. Unless force is set,
+// a pack whose version is not newer than the installed one is skipped.
+func Import(s store.Store, pack Pack, force bool) (Report, error) {
+	report := Report{Pack: pack.Name, Version: pack.Version}
+
+	if !force && pack.Version <= InstalledVersion(s, pack.Name) {
+		report.Skipped = true
+		return report, nil
+	}
+
+	err := s.Transaction(func(tx store.Store) error {
+		for _, spec := range pack.Vocabulary {
+			if err := importVocabulary(tx, spec, &report); err != nil {
+				return err
+			}
+		}
+
+		// Exercises are linked to any word in the database, not just this pack
+		words, err := tx.Words(store.WordQuery{})
+		if err != nil {
+			return err
+		}
+
+		for _, spec := range pack.Lessons {
+			if err := importLesson(tx, spec, pack.Language, words, &report); err != nil {
+				return err
+			}
+		}
+		if err := removeDroppedLessons(tx, pack, &report); err != nil {
+			return err
+		}
+
+		return tx.SetSetting(0, versionKey(pack.Name), strconv.Itoa(pack.Version))
+	})
+
+	return report, err
+}
+
+func importVocabulary(tx store.Store, spec VocabSpec, report *Report) error {
+	vocab, found := findWord(tx, spec.VocabKey(), spec.German)
+
+	vocab.Key = spec.VocabKey()
+	vocab.German = spec.German
+	vocab.English = spec.English
+	vocab.Category = spec.Category
+	vocab.Difficulty = spec.Difficulty
+	vocab.PartOfSpeech = spec.PartOfSpeech
+	vocab.Gender = spec.Gender
+	vocab.Plural = spec.Plural
+	vocab.Past = spec.Past
+	vocab.Participle = spec.Participle
+	vocab.SeparablePrefix = spec.SeparablePrefix
+	vocab.IPA = spec.IPA
+	vocab.Example = spec.Example
+	vocab.Translation = spec.Translation
+	FillGrammar(&vocab)
+
+	if err := tx.SaveWord(&vocab); err != nil {
+		return err
+	}
+
+	if found {
+		report.VocabUpdated++
+	} else {
+		report.VocabCreated++
+	}
+	return nil
+}
+
+// categoryParts guesses the part of speech of words from their category
+var categoryParts = map[string]string{
+	"verbs":     "verb",
+	"pronouns":  "pronoun",
+	"greetings": "phrase",
+}
+
+// FillGrammar completes the grammar fields of a vocabulary entry: it moves
+// a leading article into Gender, guesses a missing part of speech and
+// derives missing verb principal parts
+func FillGrammar(vocab *models.Vocabulary) {
+	if gender, noun, ok := grammar.SplitArticle(vocab.German); ok {
+		vocab.German = noun
+		if vocab.Gender == "" {
+			vocab.Gender = gender
+		}
+	}
+
+	if vocab.PartOfSpeech == "" {
+		if vocab.Gender != "" {
+			vocab.PartOfSpeech = "noun"
+		} else {
+			vocab.PartOfSpeech = categoryParts[vocab.Category]
+		}
+	}
+
+	if vocab.PartOfSpeech == "verb" && (vocab.Past == "" || vocab.Participle == "") {
+		if c, err := grammar.ConjugateSeparable(vocab.German, vocab.SeparablePrefix); err == nil {
+			if vocab.Past == "" {
+				vocab.Past = c.Forms[grammar.Preterite][0]
+			}
+			if vocab.Participle == "" {
+				vocab.Participle = c.Participle
+			}
+		}
+	}
+}
+
+func importLesson(tx store.Store, spec LessonSpec, language string, words []models.Vocabulary, report *Report) error {
+	lesson, found := findLesson(tx, spec.Key, spec.Title)
+
+	lesson.Key = spec.Key
+	lesson.Title = spec.Title
+	lesson.Description = spec.Description
+	lesson.Level = spec.Level
+	lesson.Order = spec.Order
+	lesson.XPReward = spec.XPReward
+	if language != "" {
+		lesson.Language = language
+	}
+
+	if err := tx.SaveLesson(&lesson); err != nil {
+		return err
+	}
+
+	if found {
+		report.LessonsUpdated++
+	} else {
+		report.LessonsCreated++
+	}
+
+	imported := map[string]bool{}
+	for _, exSpec := range spec.Exercises {
+		key := spec.Key + "/" + exSpec.Key
+		imported[key] = true
+		if err := importExercise(tx, lesson, key, exSpec, words, report); err != nil {
+			return err
+		}
+	}
+
+	// Exercises the pack dropped from the lesson. Unkeyed ones predate
+	// content packs and are left alone.
+	exercises, err := tx.LessonExercises(lesson.ID)
+	if err != nil {
+		return err
+	}
+	for _, exercise := range exercises {
+		if exercise.Key == "" || imported[exercise.Key] {
+			continue
+		}
+		if err := tx.DeleteExercise(exercise.ID); err != nil {
+			return err
+		}
+		report.ExercisesRemoved++
+	}
+
+	return nil
+}
+
+// removeDroppedLessons deletes the lessons an earlier import of the pack
+// installed that it no longer has, then records the pack's current lessons.
+// Lessons of other packs are never touched. Packs installed before lessons
+// were recorded have nothing to compare against until their next import.
+func removeDroppedLessons(tx store.Store, pack Pack, report *Report) error {
+	var previous []string
+	value, err := tx.Setting(0, lessonsKey(pack.Name))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	if err := decodeList(value, &previous); err != nil {
+		return err
+	}
+
+	var keys []string
+	kept := map[string]bool{}
+	for _, spec := range pack.Lessons {
+		keys = append(keys, spec.Key)
+		kept[spec.Key] = true
+	}
+
+	for _, key := range previous {
+		if kept[key] {
+			continue
+		}
+		lesson, found := findLesson(tx, key, "")
+		if !found || lesson.Key != key {
+			continue
+		}
+		if err := tx.DeleteLesson(lesson.ID); err != nil {
+			return err
+		}
+		report.LessonsRemoved++
+	}
+
+	return tx.SetSetting(0, lessonsKey(pack.Name), encodeList(keys))
+}
+
+func importExercise(tx store.Store, lesson models.Lesson, key string, spec ExerciseSpec, words []models.Vocabulary, report *Report) error {
+	exercise, found := findExercise(tx, lesson.ID, key, spec.Question)
+
+	exercise.Key = key
+	exercise.LessonID = lesson.ID
+	exercise.Type = spec.Type
+	exercise.Question = spec.Question
+	exercise.Answer = spec.Answer
+	exercise.Parameter = spec.Parameter
+	exercise.Alternatives = encodeList(spec.Alternatives)
+	exercise.Options = encodeList(spec.Options)
+	exercise.Hint = spec.Hint
+	exercise.Explanation = spec.Explanation
+	exercise.Order = spec.Order
+	exercise.Difficulty = spec.Difficulty
+	exercise.Vocabulary = exerciseWords(spec, words)
+
+	if err := tx.SaveExercise(&exercise); err != nil {
+		return err
+	}
+
+	if found {
+		report.ExercisesUpdated++
+	} else {
+		report.ExercisesCreated++
+	}
+	return nil
+}
+
+// exerciseWords returns the vocabulary an exercise practises: the words it
+// lists, otherwise the words named in its options, question and answers
+func exerciseWords(spec ExerciseSpec, words []models.Vocabulary) []models.Vocabulary {
+	keys := spec.Vocabulary
+	if len(keys) == 0 {
+		keys = spec.Options
+	}
+
+	linked := []models.Vocabulary{}
+	seen := map[uint]bool{}
+	add := func(word models.Vocabulary) {
+		if !seen[word.ID] {
+			seen[word.ID] = true
+			linked = append(linked, word)
+		}
+	}
+
+	for _, key := range keys {
+		for _, word := range words {
+			if word.Key == key {
+				add(word)
+			}
+		}
+	}
+	if len(spec.Vocabulary) > 0 {
+		return linked
+	}
+
+	text := wordTokens(strings.Join(append([]string{spec.Question, spec.Answer}, spec.Alternatives...), " "))
+	for _, word := range words {
+		if containsTokens(text, wordTokens(word.German)) {
+			add(word)
+		}
+	}
+	return linked
+}
+
+// wordTokens splits text into lowercased words
+func wordTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// containsTokens reports whether words appear in text as a run of tokens
+func containsTokens(text, words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	for i := 0; i+len(words) <= len(text); i++ {
+		match := true
+		for j, word := range words {
+			if text[i+j] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// findWord returns the word with the given key. Rows seeded before content
+// packs existed have no key, so those are matched by their German instead;
+// the same goes for findLesson and findExercise.
+func findWord(s store.VocabularyStore, key, german string) (models.Vocabulary, bool) {
+	if words, err := s.Words(store.WordQuery{Keys: []string{key}}); err == nil && len(words) > 0 {
+		return words[0], true
+	}
+
+	words, _ := s.Words(store.WordQuery{German: german})
+	for _, word := range words {
+		if word.Key == "" && word.German == german {
+			return word, true
+		}
+	}
+	return models.Vocabulary{}, false
+}
+
+// findLesson returns the lesson with the given key, or an unkeyed one with
+// the given title
+func findLesson(s store.LessonStore, key, title string) (models.Lesson, bool) {
+	lessons, _ := s.Lessons("")
+	for _, lesson := range lessons {
+		if key != "" && lesson.Key == key {
+			return lesson, true
+		}
+	}
+	for _, lesson := range lessons {
+		if lesson.Key == "" && lesson.Title == title {
+			return lesson, true
+		}
+	}
+	return models.Lesson{}, false
+}
+
+// findExercise returns the lesson's exercise with the given key, or an
+// unkeyed one with the given question
+func findExercise(s store.LessonStore, lessonID uint, key, question string) (models.Exercise, bool) {
+	exercises, _ := s.LessonExercises(lessonID)
+	for _, exercise := range exercises {
+		if exercise.Key == key {
+			return exercise, true
+		}
+	}
+	for _, exercise := range exercises {
+		if exercise.Key == "" && exercise.Question == question {
+			return exercise, true
+		}
+	}
+	return models.Exercise{}, false
+}
+
+// encodeList stores a list the way the models keep JSON columns
+func encodeList(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+func versionKey(name string) string {
+	return "content_version:" + name
+}
+
+// lessonsKey names the setting listing the lesson keys the pack installed
+func lessonsKey(name string) string {
+	return "content_lessons:" + name
+}
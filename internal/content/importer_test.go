@@ -0,0 +1,210 @@
+package content
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"testing"
+	"time"
+)
+
+// testPack returns a small pack with two lessons of two exercises each
+func testPack(version int) Pack {
+	return Pack{
+		Name:     "test",
+		Version:  version,
+		Language: "german",
+		Vocabulary: []VocabSpec{
+			{German: "der Hund", English: "dog", Category: "animals"},
+			{German: "essen", English: "to eat", Category: "verbs"},
+		},
+		Lessons: []LessonSpec{
+			{Key: "animals", Title: "Animals", Order: 1, Exercises: []ExerciseSpec{
+				{Key: "dog", Type: "translation", Question: "Translate: dog", Answer: "der Hund", Order: 1},
+				{Key: "dog-blank", Type: "fill_blank", Question: "___ Hund", Answer: "der", Vocabulary: []string{"der hund"}, Order: 2},
+			}},
+			{Key: "verbs", Title: "Verbs", Order: 2, Exercises: []ExerciseSpec{
+				{Key: "eat", Type: "translation", Question: "Translate: to eat", Answer: "essen", Order: 1},
+				{Key: "eat-blank", Type: "fill_blank", Question: "Ich ___ Brot", Answer: "esse", Order: 2},
+			}},
+		},
+	}
+}
+
+func TestImport(t *testing.T) {
+	s := store.NewMemory()
+
+	report, err := Import(s, testPack(1), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.LessonsCreated != 2 || report.ExercisesCreated != 4 || report.VocabCreated != 2 {
+		t.Errorf("first import = %+v, want 2 lessons, 4 exercises and 2 words created", report)
+	}
+	if got := InstalledVersion(s, "test"); got != 1 {
+		t.Errorf("InstalledVersion = %d, want 1", got)
+	}
+
+	words, _ := s.Words(store.WordQuery{})
+	if len(words) != 2 || words[0].German != "Hund" || words[0].Gender != "der" || words[0].Key != "der hund" {
+		t.Errorf("words = %+v, want the article split off der Hund", words)
+	}
+
+	lessons, _ := s.Lessons("german")
+	exercises, _ := s.LessonExercises(lessons[0].ID)
+	for _, exercise := range exercises {
+		ids, _ := s.ExerciseWords(exercise.ID)
+		if len(ids) != 1 || ids[0] != words[0].ID {
+			t.Errorf("exercise %q is linked to %v, want the dog", exercise.Key, ids)
+		}
+	}
+
+	report, err = Import(s, testPack(1), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Skipped {
+		t.Errorf("re-import of the installed version = %+v, want skipped", report)
+	}
+
+	report, err = Import(s, testPack(1), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.LessonsUpdated != 2 || report.ExercisesUpdated != 4 || report.VocabUpdated != 2 || report.LessonsCreated+report.ExercisesCreated+report.VocabCreated != 0 {
+		t.Errorf("forced import = %+v, want everything updated in place", report)
+	}
+}
+
+func TestImportLegacyRows(t *testing.T) {
+	s := store.NewMemory()
+
+	// Rows seeded before content packs had keys
+	lesson := models.Lesson{Title: "Animals", Order: 1}
+	if err := s.SaveLesson(&lesson); err != nil {
+		t.Fatal(err)
+	}
+	exercise := models.Exercise{LessonID: lesson.ID, Type: "translation", Question: "Translate: dog", Answer: "Hund"}
+	if err := s.SaveExercise(&exercise); err != nil {
+		t.Fatal(err)
+	}
+	word := models.Vocabulary{German: "essen", English: "eat"}
+	if err := s.SaveWord(&word); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Import(s, testPack(1), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.LessonsUpdated != 1 || report.ExercisesUpdated != 1 || report.VocabUpdated != 1 {
+		t.Errorf("import = %+v, want the legacy lesson, exercise and word updated", report)
+	}
+
+	updated, _ := s.Exercise(exercise.ID)
+	if updated.Key != "animals/dog" || updated.Answer != "der Hund" {
+		t.Errorf("legacy exercise = %+v, want it keyed and updated", updated)
+	}
+	if updated, _ := s.Word(word.ID); updated.Key != "essen" || updated.English != "to eat" {
+		t.Errorf("legacy word = %+v, want it keyed and updated", updated)
+	}
+}
+
+func TestImportRemovesDropped(t *testing.T) {
+	s := store.NewMemory()
+	if _, err := Import(s, testPack(1), false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another pack's lesson and a legacy exercise must survive
+	other := Pack{Name: "other", Version: 1, Language: "german", Lessons: []LessonSpec{
+		{Key: "colours", Title: "Colours", Order: 3, Exercises: []ExerciseSpec{
+			{Key: "red", Type: "translation", Question: "Translate: red", Answer: "rot", Order: 1},
+		}},
+	}}
+	if _, err := Import(s, other, false); err != nil {
+		t.Fatal(err)
+	}
+	animals, _ := findLesson(s, "animals", "")
+	verbs, _ := findLesson(s, "verbs", "")
+	legacy := models.Exercise{LessonID: animals.ID, Type: "translation", Question: "Translate: cat", Answer: "die Katze", Order: 9}
+	if err := s.SaveExercise(&legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	// Learner state for the content about to be dropped
+	dropped, _ := findExercise(s, animals.ID, "animals/dog-blank", "")
+	if err := s.SaveReviewItem(&models.ReviewItem{UserID: 1, ItemType: "exercise", ItemID: dropped.ID, DueAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveLessonState(&models.UserLesson{UserID: 1, LessonID: verbs.ID, Status: models.LessonCompleted}); err != nil {
+		t.Fatal(err)
+	}
+
+	pack := testPack(2)
+	pack.Lessons = pack.Lessons[:1]
+	pack.Lessons[0].Exercises = pack.Lessons[0].Exercises[:1]
+	report, err := Import(s, pack, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.LessonsRemoved != 1 || report.ExercisesRemoved != 1 {
+		t.Errorf("import = %+v, want 1 lesson and 1 exercise removed", report)
+	}
+
+	var titles []string
+	lessons, _ := s.Lessons("")
+	for _, lesson := range lessons {
+		titles = append(titles, lesson.Title)
+	}
+	if len(titles) != 2 || titles[0] != "Animals" || titles[1] != "Colours" {
+		t.Errorf("lessons = %v, want Animals and Colours", titles)
+	}
+
+	var keys []string
+	exercises, _ := s.LessonExercises(animals.ID)
+	for _, exercise := range exercises {
+		keys = append(keys, exercise.Key)
+	}
+	if len(keys) != 2 || keys[0] != "animals/dog" || keys[1] != "" {
+		t.Errorf("animals exercises = %q, want animals/dog and the legacy one", keys)
+	}
+	if _, err := s.Exercise(dropped.ID); err != store.ErrNotFound {
+		t.Errorf("dropped exercise error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.ReviewItem(1, "exercise", dropped.ID); err != store.ErrNotFound {
+		t.Errorf("review item of the dropped exercise error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.LessonState(1, verbs.ID); err != store.ErrNotFound {
+		t.Errorf("state of the dropped lesson error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFindLesson(t *testing.T) {
+	s := store.NewMemory()
+	for _, lesson := range []models.Lesson{
+		{Title: "Greetings", Order: 1},
+		{Key: "animals", Title: "Animals", Order: 2},
+	} {
+		if err := s.SaveLesson(&lesson); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		key, title string
+		want       string // Title of the lesson found, empty for none
+	}{
+		{"animals", "Tiere", "Animals"},
+		{"greetings", "Greetings", "Greetings"},
+		{"", "Greetings", "Greetings"},
+		// An empty key is not a match for every unkeyed lesson
+		{"", "Verbs", ""},
+		{"verbs", "Verbs", ""},
+	}
+	for _, tt := range tests {
+		lesson, found := findLesson(s, tt.key, tt.title)
+		if found != (tt.want != "") || lesson.Title != tt.want {
+			t.Errorf("findLesson(%q, %q) = %q, %v; want %q", tt.key, tt.title, lesson.Title, found, tt.want)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package content
+
+import (
+	"duocli/internal/grading"
+	"duocli/internal/grammar"
+	"duocli/internal/store"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// exerciseTypes are the exercise types the lesson engine knows how to run
+var exerciseTypes = map[string]bool{
+	"translation":     true,
+	"multiple_choice": true,
+	"fill_blank":      true,
+	"word_order":      true,
+	"match_pairs":     true,
+	"conjugation":     true,
+	"declension":      true,
+}
+
+// generatedTypes build their question and answer at run time from a
+// parameter instead of having a fixed answer
+var generatedTypes = map[string]bool{
+	"match_pairs": true,
+	"conjugation": true,
+	"declension":  true,
+}
+
+// Issue is a single problem found by Lint
+type Issue struct {
+	Pack     string
+	Location string
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Pack, i.Location, i.Message)
+}
+
+// Lint checks packs for content the lesson engine would trip over.
+// Vocabulary references are resolved across all of the given packs.
+func Lint(packs []Pack) []Issue {
+	var issues []Issue
+
+	vocab := vocabIndex{keys: map[string]bool{}, nouns: map[string]bool{}, categories: map[string]int{}, words: map[string]bool{}}
+	for _, pack := range packs {
+		for _, spec := range pack.Vocabulary {
+			vocab.keys[spec.VocabKey()] = true
+			vocab.categories[spec.Category]++
+			vocab.words[strings.ToLower(spec.English)] = true
+			vocab.words[strings.ToLower(spec.German)] = true
+			if spec.Gender != "" {
+				vocab.words[strings.ToLower(spec.Gender+" "+spec.German)] = true
+			}
+			if _, _, ok := grammar.SplitArticle(spec.German); ok || spec.Gender != "" {
+				vocab.nouns[spec.VocabKey()] = true
+			}
+		}
+	}
+
+	for _, pack := range packs {
+		issues = append(issues, lintPack(pack, vocab)...)
+	}
+
+	return issues
+}
+
+// vocabIndex is the vocabulary that exercises and lessons may refer to
+type vocabIndex struct {
+	keys       map[string]bool
+	nouns      map[string]bool // Keys of words with a gender
+	categories map[string]int  // Number of words per category
+	words      map[string]bool // Lowercased English and German forms
+}
+
+func lintPack(pack Pack, vocab vocabIndex) []Issue {
+	var issues []Issue
+	report := func(location, format string, args ...interface{}) {
+		issues = append(issues, Issue{Pack: pack.Name, Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if pack.Version < 1 {
+		report("pack", "version must be 1 or higher")
+	}
+
+	seenVocab := map[string]bool{}
+	for i, spec := range pack.Vocabulary {
+		location := fmt.Sprintf("vocabulary[%d] %q", i, spec.German)
+		if spec.German == "" || spec.English == "" {
+			report(location, "german and english are required")
+		}
+		if seenVocab[spec.VocabKey()] {
+			report(location, "duplicate key %q", spec.VocabKey())
+		}
+		seenVocab[spec.VocabKey()] = true
+	}
+
+	seenLessons := map[string]bool{}
+	var lessonOrders []int
+	for _, lesson := range pack.Lessons {
+		location := fmt.Sprintf("lesson %q", lesson.Key)
+		if lesson.Key == "" {
+			report(fmt.Sprintf("lesson %q", lesson.Title), "missing key")
+		} else if seenLessons[lesson.Key] {
+			report(location, "duplicate key")
+		}
+		seenLessons[lesson.Key] = true
+		lessonOrders = append(lessonOrders, lesson.Order)
+
+		if len(lesson.Exercises) == 0 {
+			report(location, "has no exercises")
+		}
+
+		seenExercises := map[string]bool{}
+		var exerciseOrders []int
+		for _, exercise := range lesson.Exercises {
+			exLocation := fmt.Sprintf("%s exercise %q", location, exercise.Key)
+			if exercise.Key == "" {
+				report(fmt.Sprintf("%s exercise %d", location, exercise.Order), "missing key")
+			} else if seenExercises[exercise.Key] {
+				report(exLocation, "duplicate key")
+			}
+			seenExercises[exercise.Key] = true
+			exerciseOrders = append(exerciseOrders, exercise.Order)
+
+			issues = append(issues, lintExercise(pack.Name, exLocation, exercise, vocab)...)
+		}
+
+		if msg := checkOrders(exerciseOrders); msg != "" {
+			report(location, "exercise orders %s", msg)
+		}
+	}
+
+	if msg := checkOrders(lessonOrders); msg != "" {
+		report("lessons", "lesson orders %s", msg)
+	}
+
+	return issues
+}
+
+func lintExercise(packName, location string, exercise ExerciseSpec, vocab vocabIndex) []Issue {
+	var issues []Issue
+	report := func(format string, args ...interface{}) {
+		issues = append(issues, Issue{Pack: packName, Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !exerciseTypes[exercise.Type] {
+		report("unknown type %q", exercise.Type)
+	}
+	if strings.TrimSpace(exercise.Question) == "" {
+		report("empty question")
+	}
+	switch {
+	case !generatedTypes[exercise.Type] && strings.TrimSpace(exercise.Answer) == "":
+		report("empty answer")
+	case generatedTypes[exercise.Type] && exercise.Answer != "":
+		report("%s has no fixed answer; put %q in parameter instead", exercise.Type, exercise.Answer)
+	}
+	for _, key := range exercise.Vocabulary {
+		if !vocab.keys[key] {
+			report("references unknown vocabulary %q", key)
+		}
+	}
+
+	switch exercise.Type {
+	case "multiple_choice":
+		// Without options, distractors are drawn from the vocabulary, so the
+		// answer has to be a known word
+		if len(exercise.Options) == 0 {
+			if !vocab.words[strings.ToLower(exercise.Answer)] {
+				report("multiple choice without options needs an answer from the vocabulary, got %q", exercise.Answer)
+			}
+			break
+		}
+		if len(exercise.Options) < 2 {
+			report("multiple choice needs at least two options")
+		}
+		found := false
+		for _, option := range exercise.Options {
+			if strings.EqualFold(option, exercise.Answer) {
+				found = true
+			}
+		}
+		if !found {
+			report("answer %q is not one of the options", exercise.Answer)
+		}
+	case "fill_blank":
+		if !strings.Contains(exercise.Question, "___") {
+			report("fill_blank question has no blank (___)")
+		}
+	case "match_pairs":
+		// Options list vocabulary keys; without them the parameter names
+		// the category to draw words from
+		for _, key := range exercise.Options {
+			if !vocab.keys[key] {
+				report("references unknown vocabulary %q", key)
+			}
+		}
+		if len(exercise.Options) == 1 {
+			report("match_pairs needs at least two words")
+		}
+		if len(exercise.Options) == 0 && vocab.categories[exercise.Parameter] < 2 {
+			report("vocabulary category %q has fewer than two words", exercise.Parameter)
+		}
+	case "conjugation":
+		// The parameter names the tense to ask; the verbs come from the
+		// options or the verbs category
+		if _, ok := grammar.ParseTense(exercise.Parameter); !ok && exercise.Parameter != "any" {
+			report("conjugation parameter %q is not a tense (present, präteritum, perfekt or any)", exercise.Parameter)
+		}
+		for _, key := range exercise.Options {
+			if !vocab.keys[key] {
+				report("references unknown vocabulary %q", key)
+			}
+		}
+		if len(exercise.Options) == 0 && vocab.categories["verbs"] == 0 {
+			report("conjugation needs options or words in the verbs category")
+		}
+	case "declension":
+		// The parameter names the case to ask; the nouns come from the
+		// options or the whole vocabulary
+		if _, ok := grammar.ParseCase(exercise.Parameter); !ok && exercise.Parameter != "any" {
+			report("declension parameter %q is not a case (nominative, accusative, dative, genitive or any)", exercise.Parameter)
+		}
+		nouns := 0
+		for _, key := range exercise.Options {
+			if !vocab.keys[key] {
+				report("references unknown vocabulary %q", key)
+			}
+			if vocab.nouns[key] {
+				nouns++
+			}
+		}
+		if len(exercise.Options) > 0 && nouns == 0 {
+			report("declension options include no nouns with a gender")
+		}
+		if len(exercise.Options) == 0 && len(vocab.nouns) == 0 {
+			report("declension needs nouns with a gender in the vocabulary")
+		}
+	case "word_order":
+		words := wordSet(grading.Tokens(exercise.Answer))
+		if len(exercise.Options) > 0 && wordSet(exercise.Options) != words {
+			report("word_order options do not match the words of the answer")
+		}
+		for _, alternative := range exercise.Alternatives {
+			if wordSet(grading.Tokens(alternative)) != words {
+				report("alternative %q does not use the same words as the answer", alternative)
+			}
+		}
+	}
+
+	return issues
+}
+
+// wordSet returns the words, lowercased and sorted, so two sentences made
+// of the same words compare equal
+func wordSet(words []string) string {
+	lowered := make([]string, len(words))
+	for i, word := range words {
+		lowered[i] = strings.ToLower(word)
+	}
+	sort.Strings(lowered)
+	return strings.Join(lowered, " ")
+}
+
+// checkOrders reports orders that are not exactly 1..n
+func checkOrders(orders []int) string {
+	if len(orders) == 0 {
+		return ""
+	}
+
+	sorted := append([]int(nil), orders...)
+	sort.Ints(sorted)
+	for i, order := range sorted {
+		if order != i+1 {
+			return fmt.Sprintf("are not contiguous from 1 (got %v)", sorted)
+		}
+	}
+	return ""
+}
+
+// FromStore rebuilds packs from the content installed in s so it can be
+// linted: one with the vocabulary, then one per course with its lessons, as
+// lesson orders only need to be unique within a course. JSON columns that
+// fail to parse are reported as issues.
+func FromStore(s store.Store) ([]Pack, []Issue) {
+	pack := Pack{Name: "database", Version: 1}
+	var issues []Issue
+
+	vocabularies, _ := s.Words(store.WordQuery{})
+	for _, vocab := range vocabularies {
+		pack.Vocabulary = append(pack.Vocabulary, VocabSpec{
+			Key:             vocab.Key,
+			German:          vocab.German,
+			English:         vocab.English,
+			Category:        vocab.Category,
+			Difficulty:      vocab.Difficulty,
+			PartOfSpeech:    vocab.PartOfSpeech,
+			Gender:          vocab.Gender,
+			Plural:          vocab.Plural,
+			Past:            vocab.Past,
+			Participle:      vocab.Participle,
+			SeparablePrefix: vocab.SeparablePrefix,
+			IPA:             vocab.IPA,
+		})
+	}
+
+	packs := []Pack{pack}
+	courses := map[string]int{} // Index of each course's pack
+	lessons, _ := s.Lessons("")
+	for _, lesson := range lessons {
+		i, ok := courses[lesson.Language]
+		if !ok {
+			i = len(packs)
+			courses[lesson.Language] = i
+			packs = append(packs, Pack{Name: "database/" + lesson.Language, Version: 1, Language: lesson.Language})
+		}
+		course := &packs[i]
+
+		spec := LessonSpec{
+			Key:      lesson.Key,
+			Title:    lesson.Title,
+			Level:    lesson.Level,
+			Order:    lesson.Order,
+			XPReward: lesson.XPReward,
+		}
+		if spec.Key == "" {
+			spec.Key = fmt.Sprintf("#%d", lesson.ID)
+		}
+
+		exercises, _ := s.LessonExercises(lesson.ID)
+		for _, exercise := range exercises {
+			exSpec := ExerciseSpec{
+				Key:       exercise.Key,
+				Type:      exercise.Type,
+				Question:  exercise.Question,
+				Answer:    exercise.Answer,
+				Parameter: exercise.Parameter,
+				Order:     exercise.Order,
+			}
+			if exSpec.Key == "" {
+				exSpec.Key = fmt.Sprintf("#%d", exercise.ID)
+			}
+
+			location := fmt.Sprintf("lesson %q exercise %q", spec.Key, exSpec.Key)
+			if err := decodeList(exercise.Options, &exSpec.Options); err != nil {
+				issues = append(issues, Issue{Pack: course.Name, Location: location, Message: "options do not parse: " + err.Error()})
+			}
+			if err := decodeList(exercise.Alternatives, &exSpec.Alternatives); err != nil {
+				issues = append(issues, Issue{Pack: course.Name, Location: location, Message: "alternatives do not parse: " + err.Error()})
+			}
+
+			spec.Exercises = append(spec.Exercises, exSpec)
+		}
+
+		course.Lessons = append(course.Lessons, spec)
+	}
+
+	return packs, issues
+}
+
+func decodeList(value string, dest *[]string) error {
+	if value == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(value), dest)
+}
@@ -0,0 +1,131 @@
+package content
+
+import (
+	"duocli/internal/store"
+	"strings"
+	"testing"
+)
+
+func TestLintEmbedded(t *testing.T) {
+	packs, err := Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range Lint(packs) {
+		t.Errorf("built-in pack: %s", issue)
+	}
+}
+
+func TestLint(t *testing.T) {
+	if issues := Lint([]Pack{testPack(1)}); len(issues) > 0 {
+		t.Fatalf("Lint(testPack) = %v, want no issues", issues)
+	}
+
+	tests := []struct {
+		name   string
+		change func(p *Pack)
+		want   string
+	}{
+		{"version", func(p *Pack) { p.Version = 0 }, "version must be 1 or higher"},
+		{"vocabulary", func(p *Pack) { p.Vocabulary[1].English = "" }, "german and english are required"},
+		{"duplicate word", func(p *Pack) { p.Vocabulary[1].German = "der Hund" }, `duplicate key "der hund"`},
+		{"lesson key", func(p *Pack) { p.Lessons[1].Key = "animals" }, "duplicate key"},
+		{"no exercises", func(p *Pack) { p.Lessons[1].Exercises = nil }, "has no exercises"},
+		{"lesson orders", func(p *Pack) { p.Lessons[1].Order = 3 }, "lesson orders are not contiguous"},
+		{"exercise orders", func(p *Pack) { p.Lessons[0].Exercises[1].Order = 1 }, "exercise orders are not contiguous"},
+		{"exercise key", func(p *Pack) { p.Lessons[0].Exercises[1].Key = "" }, "missing key"},
+		{"type", func(p *Pack) { p.Lessons[0].Exercises[0].Type = "essay" }, `unknown type "essay"`},
+		{"answer", func(p *Pack) { p.Lessons[0].Exercises[0].Answer = " " }, "empty answer"},
+		{"blank", func(p *Pack) { p.Lessons[0].Exercises[1].Question = "Hund" }, "has no blank"},
+		{"unknown word", func(p *Pack) { p.Lessons[0].Exercises[1].Vocabulary = []string{"katze"} }, `unknown vocabulary "katze"`},
+		{"choice answer", func(p *Pack) {
+			p.Lessons[0].Exercises[0].Type = "multiple_choice"
+			p.Lessons[0].Exercises[0].Options = []string{"die Katze", "das Haus"}
+		}, `answer "der Hund" is not one of the options`},
+		{"choice vocabulary", func(p *Pack) {
+			p.Lessons[0].Exercises[0].Type = "multiple_choice"
+			p.Lessons[0].Exercises[0].Answer = "Katze"
+		}, "needs an answer from the vocabulary"},
+		{"generated answer", func(p *Pack) { p.Lessons[0].Exercises[0].Type = "match_pairs" }, "match_pairs has no fixed answer"},
+		{"pairs category", func(p *Pack) {
+			p.Lessons[0].Exercises[0].Type = "match_pairs"
+			p.Lessons[0].Exercises[0].Answer = ""
+			p.Lessons[0].Exercises[0].Parameter = "animals"
+		}, `category "animals" has fewer than two words`},
+		{"tense", func(p *Pack) {
+			p.Lessons[1].Exercises[0].Type = "conjugation"
+			p.Lessons[1].Exercises[0].Answer = ""
+			p.Lessons[1].Exercises[0].Parameter = "future"
+		}, `"future" is not a tense`},
+		{"case", func(p *Pack) {
+			p.Lessons[1].Exercises[0].Type = "declension"
+			p.Lessons[1].Exercises[0].Answer = ""
+			p.Lessons[1].Exercises[0].Parameter = "dative"
+			p.Lessons[1].Exercises[0].Options = []string{"essen"}
+		}, "options include no nouns"},
+		{"word order", func(p *Pack) {
+			p.Lessons[1].Exercises[0].Type = "word_order"
+			p.Lessons[1].Exercises[0].Answer = "ich esse Brot"
+			p.Lessons[1].Exercises[0].Options = []string{"ich", "esse", "Wurst"}
+		}, "options do not match the words of the answer"},
+	}
+
+	for _, tt := range tests {
+		pack := testPack(1)
+		tt.change(&pack)
+
+		var messages []string
+		for _, issue := range Lint([]Pack{pack}) {
+			messages = append(messages, issue.String())
+		}
+		if !strings.Contains(strings.Join(messages, "\n"), tt.want) {
+			t.Errorf("%s: Lint = %q, want an issue containing %q", tt.name, messages, tt.want)
+		}
+	}
+}
+
+func TestFromStore(t *testing.T) {
+	s := store.NewMemory()
+	if _, err := Import(s, testPack(1), false); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second course numbers its lessons from 1 again
+	spanish := testPack(1)
+	spanish.Name, spanish.Language = "spanish", "spanish"
+	for i := range spanish.Lessons {
+		spanish.Lessons[i].Key = "es-" + spanish.Lessons[i].Key
+	}
+	if _, err := Import(s, spanish, false); err != nil {
+		t.Fatal(err)
+	}
+
+	packs, issues := FromStore(s)
+	if len(issues) > 0 {
+		t.Fatalf("FromStore issues = %v, want none", issues)
+	}
+	if issues := Lint(packs); len(issues) > 0 {
+		t.Errorf("Lint(FromStore) = %v, want no issues", issues)
+	}
+	if len(packs) != 3 || len(packs[0].Vocabulary) != 2 || len(packs[0].Lessons) != 0 {
+		t.Fatalf("FromStore = %+v, want the vocabulary and then a pack per course", packs)
+	}
+	for _, pack := range packs[1:] {
+		if len(pack.Lessons) != 2 || len(pack.Lessons[0].Exercises) != 2 {
+			t.Errorf("course %q = %+v, want 2 lessons of 2 exercises", pack.Language, pack.Lessons)
+		}
+	}
+
+	// A JSON column that no longer parses
+	lessons, _ := s.Lessons("german")
+	exercises, _ := s.LessonExercises(lessons[0].ID)
+	broken := exercises[0]
+	broken.Options = "[not json"
+	if err := s.SaveExercise(&broken); err != nil {
+		t.Fatal(err)
+	}
+	_, issues = FromStore(s)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "options do not parse") || issues[0].Pack != "database/german" {
+		t.Errorf("FromStore issues = %v, want one about the german options", issues)
+	}
+}
@@ -0,0 +1,143 @@
+package content
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed packs/*.json
+var embedded embed.FS
+
+// Pack is a versioned bundle of course content
+type Pack struct {
+	Name       string       `json:"name"`
+	Version    int          `json:"version"`
+	Language   string       `json:"language"`
+	Vocabulary []VocabSpec  `json:"vocabulary"`
+	Lessons    []LessonSpec `json:"lessons"`
+}
+
+// LessonSpec describes a lesson and its exercises. The words a lesson
+// teaches are linked through its exercises.
+type LessonSpec struct {
+	Key         string         `json:"key"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Level       int            `json:"level"`
+	Order       int            `json:"order"`
+	XPReward    int            `json:"xp_reward"`
+	Exercises   []ExerciseSpec `json:"exercises"`
+}
+
+// ExerciseSpec describes a single exercise. Keys only need to be unique
+// within their lesson.
+type ExerciseSpec struct {
+	Key          string   `json:"key"`
+	Type         string   `json:"type"`
+	Question     string   `json:"question"`
+	Answer       string   `json:"answer,omitempty"`
+	Parameter    string   `json:"parameter,omitempty"` // Tense, case or category for conjugation, declension and match_pairs
+	Alternatives []string `json:"alternatives,omitempty"`
+	Options      []string `json:"options,omitempty"`
+	Vocabulary   []string `json:"vocabulary,omitempty"` // Keys of the words practised, found automatically when empty
+	Hint         string   `json:"hint,omitempty"`
+	Explanation  string   `json:"explanation,omitempty"`
+	Order        int      `json:"order"`
+	Difficulty   int      `json:"difficulty"`
+}
+
+// VocabSpec describes a vocabulary entry. When Key is empty the lowercased
+// German word is used. Nouns may be written with their article ("der Hund");
+// the importer moves it into Gender.
+type VocabSpec struct {
+	Key             string `json:"key,omitempty"`
+	German          string `json:"german"`
+	English         string `json:"english"`
+	Category        string `json:"category"`
+	Difficulty      int    `json:"difficulty"`
+	PartOfSpeech    string `json:"pos,omitempty"`
+	Gender          string `json:"gender,omitempty"`
+	Plural          string `json:"plural,omitempty"`
+	Past            string `json:"past,omitempty"`
+	Participle      string `json:"participle,omitempty"`
+	SeparablePrefix string `json:"separable_prefix,omitempty"`
+	IPA             string `json:"ipa,omitempty"`
+	Example         string `json:"example,omitempty"`
+	Translation     string `json:"translation,omitempty"`
+}
+
+// VocabKey returns the stable key for a vocabulary entry
+func (v VocabSpec) VocabKey() string {
+	if v.Key != "" {
+		return v.Key
+	}
+	return strings.ToLower(v.German)
+}
+
+// Embedded returns the content packs compiled into the binary
+func Embedded() ([]Pack, error) {
+	return loadFS(embedded, "packs")
+}
+
+// Load reads a single pack file, or every *.json pack in a directory
+func Load(filename string) ([]Pack, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return loadFS(os.DirFS(filename), ".")
+	}
+
+	pack, err := loadFile(os.DirFS(filepath.Dir(filename)), filepath.Base(filename))
+	if err != nil {
+		return nil, err
+	}
+	return []Pack{pack}, nil
+}
+
+func loadFS(fsys fs.FS, dir string) ([]Pack, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var packs []Pack
+	for _, name := range names {
+		pack, err := loadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, pack)
+	}
+
+	return packs, nil
+}
+
+func loadFile(fsys fs.FS, name string) (Pack, error) {
+	var pack Pack
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return pack, err
+	}
+
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return pack, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if pack.Name == "" {
+		return pack, fmt.Errorf("%s: pack has no name", name)
+	}
+
+	return pack, nil
+}
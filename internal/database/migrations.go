@@ -0,0 +1,353 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is one step of the schema history. Steps run in version order,
+// each in its own transaction, and are recorded in schema_migrations.
+//
+// Steps work on the snapshot structs in schema.go, never on the current
+// models, so what a shipped step does cannot change with the models.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // nil when the step cannot be undone
+}
+
+// Migrations is the schema history, oldest first. Append new steps with the
+// next version; never change or reorder steps that have shipped.
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_core_tables",
+		Up:      createCoreTables,
+	},
+	{
+		Version: 2,
+		Name:    "add_gender_answers",
+		Up:      createTable(&genderAnswerV2{}),
+		Down:    dropTable(&genderAnswerV2{}),
+	},
+	{
+		Version: 3,
+		Name:    "add_vocab_answers",
+		Up:      createTable(&vocabAnswerV3{}),
+		Down:    dropTable(&vocabAnswerV3{}),
+	},
+	{
+		Version: 4,
+		Name:    "add_word_strengths",
+		Up:      createTable(&wordStrengthV4{}),
+		Down:    dropTable(&wordStrengthV4{}),
+	},
+	{
+		Version: 5,
+		Name:    "fill_vocabulary_grammar",
+		Up:      fillVocabularyGrammar,
+		Down:    keepData,
+	},
+	{
+		Version: 6,
+		Name:    "add_exercise_parameters",
+		Up:      addExerciseParameters,
+		Down:    dropExerciseParameters,
+	},
+}
+
+// schemaMigration records an applied migration
+type schemaMigration struct {
+	Version   int `gorm:"primarykey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus is a migration with when it was applied, if it was
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+	Unknown   bool // Applied by a newer DuoCLI; not in Migrations
+}
+
+// LatestVersion returns the schema version this build migrates to
+func LatestVersion() int {
+	return Migrations[len(Migrations)-1].Version
+}
+
+// Status lists every known migration along with any unknown ones recorded
+// in the database, in version order
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	known := map[int]bool{}
+	for _, m := range Migrations {
+		known[m.Version] = true
+		status := MigrationStatus{Migration: m}
+		if row, ok := applied[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, row := range applied {
+		if !known[version] {
+			appliedAt := row.AppliedAt
+			statuses = append(statuses, MigrationStatus{
+				Migration: Migration{Version: version, Name: row.Name},
+				AppliedAt: &appliedAt,
+				Unknown:   true,
+			})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Migrate applies every pending migration to the database at path. When
+// there is existing data it is backed up first; backup is the copy's path,
+// or "" when none was needed.
+func Migrate(db *gorm.DB, path string) (applied []Migration, backup string, err error) {
+	done, err := appliedMigrations(db)
+	if err != nil {
+		return nil, "", err
+	}
+	for version := range done {
+		if version > LatestVersion() {
+			return nil, "", fmt.Errorf("the database is at schema version %d but this DuoCLI only knows up to %d; please upgrade DuoCLI", version, LatestVersion())
+		}
+	}
+
+	var pending []Migration
+	for _, m := range Migrations {
+		if _, ok := done[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, "", nil
+	}
+
+	// A brand new database has nothing worth keeping
+	if db.Migrator().HasTable(&userV1{}) {
+		if backup, err = Backup(db, path); err != nil {
+			return nil, "", fmt.Errorf("failed to back up the database before migrating: %w", err)
+		}
+	}
+
+	for _, m := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return applied, backup, migrationError("migration", m, backup, err)
+		}
+		applied = append(applied, m)
+	}
+
+	return applied, backup, nil
+}
+
+// RollbackTargets returns the migrations Rollback would undo for steps,
+// newest first, or an error if any of them cannot be undone
+func RollbackTargets(db *gorm.DB, steps int) ([]Migration, error) {
+	done, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []int
+	for version := range done {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	if steps < len(versions) {
+		versions = versions[:steps]
+	}
+
+	var targets []Migration
+	for _, version := range versions {
+		m, ok := findMigration(version)
+		if !ok {
+			return nil, fmt.Errorf("migration %d was applied by a newer DuoCLI and cannot be rolled back by this one", version)
+		}
+		if m.Down == nil {
+			return nil, fmt.Errorf("migration %d %s cannot be rolled back", m.Version, m.Name)
+		}
+		targets = append(targets, m)
+	}
+	return targets, nil
+}
+
+// Rollback undoes the last steps applied migrations, newest first, after
+// backing the database up. It refuses to start if any of them cannot be
+// undone.
+func Rollback(db *gorm.DB, path string, steps int) (rolledBack []Migration, backup string, err error) {
+	targets, err := RollbackTargets(db, steps)
+	if err != nil || len(targets) == 0 {
+		return nil, "", err
+	}
+
+	if backup, err = Backup(db, path); err != nil {
+		return nil, "", fmt.Errorf("failed to back up the database before rolling back: %w", err)
+	}
+
+	for _, m := range targets {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return rolledBack, backup, migrationError("rollback of", m, backup, err)
+		}
+		rolledBack = append(rolledBack, m)
+	}
+
+	return rolledBack, backup, nil
+}
+
+// Backup writes a consistent copy of the database at path next to it and
+// returns the copy's path
+func Backup(db *gorm.DB, path string) (string, error) {
+	stamp := time.Now().Format("20060102-150405")
+	target := fmt.Sprintf("%s.%s.bak", path, stamp)
+	for n := 2; fileExists(target); n++ {
+		target = fmt.Sprintf("%s.%s-%d.bak", path, stamp, n)
+	}
+	if err := db.Exec("VACUUM INTO ?", target).Error; err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+func appliedMigrations(db *gorm.DB) (map[int]schemaMigration, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func findMigration(version int) (Migration, bool) {
+	for _, m := range Migrations {
+		if m.Version == version {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+func migrationError(what string, m Migration, backup string, err error) error {
+	if backup != "" {
+		return fmt.Errorf("%s %d %s failed: %w (your data was backed up to %s)", what, m.Version, m.Name, err, backup)
+	}
+	return fmt.Errorf("%s %d %s failed: %w", what, m.Version, m.Name, err)
+}
+
+func createTable(model interface{}) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.AutoMigrate(model)
+	}
+}
+
+func dropTable(model interface{}) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(model)
+	}
+}
+
+// keepData undoes a data-only step, whose changes are fine to keep
+func keepData(tx *gorm.DB) error {
+	return nil
+}
+
+// generatedTypes are the exercise types that used to keep their tense, case
+// or category in the answer column
+var generatedTypes = []string{"conjugation", "declension", "match_pairs"}
+
+// addExerciseParameters moves what generated exercises ask about out of the
+// answer column into its own
+func addExerciseParameters(tx *gorm.DB) error {
+	if err := tx.Migrator().AddColumn(&exerciseV6{}, "Parameter"); err != nil {
+		return err
+	}
+	return tx.Exec("UPDATE exercises SET parameter = answer, answer = '' WHERE type IN ?", generatedTypes).Error
+}
+
+func dropExerciseParameters(tx *gorm.DB) error {
+	if err := tx.Exec("UPDATE exercises SET answer = parameter WHERE type IN ?", generatedTypes).Error; err != nil {
+		return err
+	}
+	return tx.Migrator().DropColumn(&exerciseV6{}, "Parameter")
+}
+
+// createCoreTables brings the tables that predate versioned migrations up to
+// date, converting data left behind by older versions on the way
+func createCoreTables(tx *gorm.DB) error {
+	// Progress rows from before partial credit need backfilling once
+	hadCredit := tx.Migrator().HasColumn(&progressV1{}, "credit")
+
+	err := tx.AutoMigrate(
+		&userV1{},
+		&lessonV1{},
+		&exerciseV1{},
+		&exerciseVocabularyV1{},
+		&progressV1{},
+		&vocabularyV1{},
+		&reviewItemV1{},
+		&userLessonV1{},
+		&settingV1{},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Move the old global completion flag onto per-user lesson state
+	if err := migrateLessonCompletion(tx); err != nil {
+		return err
+	}
+
+	// Split legacy "A/B" answers into a canonical answer plus alternatives
+	if err := migrateAnswerAlternatives(tx); err != nil {
+		return err
+	}
+
+	// Correct answers recorded before partial credit earned full credit
+	if !hadCredit {
+		return tx.Model(&progressV1{}).Where("is_correct = ?", true).Update("credit", 1).Error
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"duocli/internal/content"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func migrated(t *testing.T) (*gorm.DB, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "duocli.db")
+	db, err := Connect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Migrate(db, path); err != nil {
+		t.Fatal(err)
+	}
+	return db, path
+}
+
+func TestMigrateFresh(t *testing.T) {
+	db, path := migrated(t)
+
+	statuses, err := Status(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != LatestVersion() {
+		t.Fatalf("got %d migrations, want %d", len(statuses), LatestVersion())
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("migration %d %s was not applied", s.Version, s.Name)
+		}
+	}
+
+	// Running again has nothing to do
+	applied, backup, err := Migrate(db, path)
+	if err != nil || len(applied) != 0 || backup != "" {
+		t.Errorf("second Migrate applied %d, backup %q, err %v; want nothing", len(applied), backup, err)
+	}
+}
+
+func TestExerciseParametersRoundTrip(t *testing.T) {
+	db, path := migrated(t)
+	if _, _, err := Rollback(db, path, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Before version 6 the tense was kept in the answer
+	if err := db.Exec("INSERT INTO exercises (type, question, answer) VALUES ('conjugation', 'Conjugate', 'present'), ('translation', 'Hallo', 'Hello')").Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Migrate(db, path); err != nil {
+		t.Fatal(err)
+	}
+
+	var rows []struct{ Type, Answer, Parameter string }
+	db.Raw("SELECT type, answer, parameter FROM exercises ORDER BY id").Scan(&rows)
+	if len(rows) != 2 || rows[0].Answer != "" || rows[0].Parameter != "present" || rows[1].Answer != "Hello" || rows[1].Parameter != "" {
+		t.Errorf("after migrating: %+v", rows)
+	}
+
+	if _, _, err := Rollback(db, path, 1); err != nil {
+		t.Fatal(err)
+	}
+	var answer string
+	db.Raw("SELECT answer FROM exercises WHERE type = 'conjugation'").Scan(&answer)
+	if answer != "present" {
+		t.Errorf("after rolling back the answer is %q, want present", answer)
+	}
+}
+
+func TestFillVocabularyGrammar(t *testing.T) {
+	db, path := migrated(t)
+	if _, _, err := Rollback(db, path, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Exec("INSERT INTO vocabularies (german, english) VALUES ('der Hund', 'the dog')").Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Migrate(db, path); err != nil {
+		t.Fatal(err)
+	}
+
+	var word vocabularyV1
+	if err := db.Where("english = ?", "the dog").First(&word).Error; err != nil {
+		t.Fatal(err)
+	}
+	if word.German != "Hund" || word.Gender != "der" || word.PartOfSpeech != "noun" {
+		t.Errorf("got %q, %q, %q; want Hund, der, noun", word.German, word.Gender, word.PartOfSpeech)
+	}
+}
+
+func TestUpgradeBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "duocli.db")
+	db, err := Connect(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The tables and seed rows of the first release, before content packs
+	err = db.AutoMigrate(&baselineUser{}, &baselineLesson{}, &baselineExercise{}, &baselineProgress{}, &baselineVocabulary{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&baselineUser{Name: "Learner"})
+	db.Create(&baselineLesson{Title: "Basic Greetings", Level: 1, Order: 1, IsCompleted: true})
+	db.Create(&[]baselineVocabulary{
+		{German: "Hallo", English: "Hello", Category: "greetings"},
+		{German: "der Hund", English: "the dog", Category: "animals"},
+		{German: "die Katze", English: "the cat", Category: "animals"},
+		{German: "essen", English: "to eat", Category: "verbs"},
+	})
+
+	if _, err := Open(path); err != nil {
+		t.Fatal(err)
+	}
+
+	packs, err := content.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := map[string]bool{}
+	for _, pack := range packs {
+		for _, spec := range pack.Vocabulary {
+			keys[spec.VocabKey()] = true
+		}
+	}
+
+	var total int64
+	db.Table("vocabularies").Count(&total)
+	if total != int64(len(keys)) {
+		t.Errorf("got %d words after upgrading, want the %d in the packs", total, len(keys))
+	}
+
+	for _, english := range []string{"Hello", "the dog", "the cat", "to eat"} {
+		var rows []vocabularyV1
+		db.Where("english = ?", english).Find(&rows)
+		if len(rows) != 1 || rows[0].Key == "" {
+			t.Errorf("%s: got %+v, want one keyed row", english, rows)
+		}
+	}
+}
+
+// The models of the first release, which created its tables with AutoMigrate
+
+type baselineUser struct {
+	ID        uint `gorm:"primarykey"`
+	Name      string
+	Level     int `gorm:"default:1"`
+	XP        int `gorm:"default:0"`
+	Streak    int `gorm:"default:0"`
+	LastSeen  time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineLesson struct {
+	ID          uint `gorm:"primarykey"`
+	Title       string
+	Description string
+	Level       int
+	Order       int
+	XPReward    int    `gorm:"default:10"`
+	IsCompleted bool   `gorm:"default:false"`
+	Language    string `gorm:"default:german"`
+}
+
+func (baselineLesson) TableName() string { return "lessons" }
+
+type baselineExercise struct {
+	ID          uint `gorm:"primarykey"`
+	LessonID    uint
+	Type        string
+	Question    string
+	Answer      string
+	Options     string
+	Hint        string
+	Explanation string
+	Order       int
+	Difficulty  int `gorm:"default:1"`
+}
+
+func (baselineExercise) TableName() string { return "exercises" }
+
+type baselineProgress struct {
+	ID          uint `gorm:"primarykey"`
+	UserID      uint
+	LessonID    uint
+	ExerciseID  uint
+	IsCorrect   bool
+	Attempts    int `gorm:"default:1"`
+	CompletedAt time.Time
+}
+
+func (baselineProgress) TableName() string { return "progresses" }
+
+type baselineVocabulary struct {
+	ID          uint `gorm:"primarykey"`
+	German      string
+	English     string
+	Category    string
+	Difficulty  int `gorm:"default:1"`
+	AudioURL    string
+	Example     string
+	Translation string
+}
+
+func (baselineVocabulary) TableName() string { return "vocabularies" }
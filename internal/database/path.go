@@ -0,0 +1,130 @@
+package database
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LegacyPath is where versions before XDG support kept the database,
+// relative to the current directory
+const LegacyPath = "duocli.db"
+
+// Path resolves where the database lives: flag (from --db) wins, then the
+// DUOCLI_DB environment variable, then $XDG_DATA_HOME/duocli/duocli.db,
+// with XDG_DATA_HOME defaulting to ~/.local/share. explicit reports whether
+// the user chose the location.
+func Path(flag string) (path string, explicit bool, err error) {
+	if flag != "" {
+		return flag, true, nil
+	}
+	if env := os.Getenv("DUOCLI_DB"); env != "" {
+		return env, true, nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false, fmt.Errorf("cannot find the home directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "duocli", "duocli.db"), false, nil
+}
+
+// LegacyDatabase reports whether a database left in the current directory
+// by an older version should be offered for moving to path: it exists,
+// isn't path itself, and nothing is at path yet
+func LegacyDatabase(path string) bool {
+	legacy, err := filepath.Abs(LegacyPath)
+	if err != nil {
+		return false
+	}
+	target, err := filepath.Abs(path)
+	if err != nil || legacy == target {
+		return false
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		return false
+	}
+	_, err = os.Stat(target)
+	return os.IsNotExist(err)
+}
+
+// MoveLegacy moves the database in the current directory to path, copying
+// it when a rename is not possible (e.g. across filesystems)
+func MoveLegacy(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(LegacyPath, path); err == nil {
+		return nil
+	}
+
+	if err := copyFile(LegacyPath, path); err != nil {
+		os.Remove(path)
+		return err
+	}
+	return os.Remove(LegacyPath)
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// LegacyDeclined reports whether the user turned down moving the database
+// in the current directory before, as recorded in the file at marker
+func LegacyDeclined(marker string) bool {
+	legacy, err := filepath.Abs(LegacyPath)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(marker)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == legacy {
+			return true
+		}
+	}
+	return false
+}
+
+// DeclineLegacy records in the file at marker that the database in the
+// current directory should not be offered for moving again
+func DeclineLegacy(marker string) error {
+	legacy, err := filepath.Abs(LegacyPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(marker), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(marker, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, legacy); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
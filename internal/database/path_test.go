@@ -0,0 +1,50 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeclineLegacy(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	if err := os.WriteFile(LegacyPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(dir, "data", "duocli.db")
+	marker := filepath.Join(dir, "config", "legacy-db-declined")
+
+	if !LegacyDatabase(target) {
+		t.Fatal("LegacyDatabase() = false, want true")
+	}
+	if LegacyDeclined(marker) {
+		t.Fatal("LegacyDeclined() = true before declining")
+	}
+	if err := DeclineLegacy(marker); err != nil {
+		t.Fatal(err)
+	}
+	if !LegacyDeclined(marker) {
+		t.Error("LegacyDeclined() = false after declining")
+	}
+
+	// A database in another directory is still offered
+	other := filepath.Join(dir, "other")
+	if err := os.Mkdir(other, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(other); err != nil {
+		t.Fatal(err)
+	}
+	if LegacyDeclined(marker) {
+		t.Error("LegacyDeclined() = true for a different directory")
+	}
+}
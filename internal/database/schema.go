@@ -0,0 +1,189 @@
+package database
+
+import "time"
+
+// The structs below are snapshots of the tables as migrations created them.
+// Migrations use these rather than the current models, so that changing a
+// model never changes what an already shipped migration does. Later schema
+// changes get a new migration, not an edit here.
+
+// Version 1: the core tables
+
+type userV1 struct {
+	ID              uint `gorm:"primarykey"`
+	Name            string
+	Level           int `gorm:"default:1"`
+	XP              int `gorm:"default:0"`
+	Streak          int `gorm:"default:0"`
+	Hearts          int `gorm:"default:5"`
+	HeartsUpdatedAt time.Time
+	LastSeen        time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (userV1) TableName() string { return "users" }
+
+type lessonV1 struct {
+	ID          uint   `gorm:"primarykey"`
+	Key         string `gorm:"index:idx_lessons_key"`
+	Title       string
+	Description string
+	Level       int
+	Order       int
+	XPReward    int    `gorm:"default:10"`
+	Language    string `gorm:"default:german"`
+}
+
+func (lessonV1) TableName() string { return "lessons" }
+
+type exerciseV1 struct {
+	ID           uint `gorm:"primarykey"`
+	LessonID     uint
+	Key          string `gorm:"index:idx_exercises_key"`
+	Type         string
+	Question     string
+	Answer       string
+	Alternatives string
+	Options      string
+	Hint         string
+	Explanation  string
+	Order        int
+	Difficulty   int      `gorm:"default:1"`
+	Lesson       lessonV1 `gorm:"foreignKey:LessonID"`
+}
+
+func (exerciseV1) TableName() string { return "exercises" }
+
+type exerciseVocabularyV1 struct {
+	ExerciseID   uint         `gorm:"primaryKey"`
+	VocabularyID uint         `gorm:"primaryKey"`
+	Exercise     exerciseV1   `gorm:"foreignKey:ExerciseID"`
+	Vocabulary   vocabularyV1 `gorm:"foreignKey:VocabularyID"`
+}
+
+func (exerciseVocabularyV1) TableName() string { return "exercise_vocabulary" }
+
+type progressV1 struct {
+	ID          uint `gorm:"primarykey"`
+	UserID      uint
+	LessonID    uint
+	ExerciseID  uint
+	IsCorrect   bool
+	Credit      float64
+	Attempts    int `gorm:"default:1"`
+	CompletedAt time.Time
+	User        userV1     `gorm:"foreignKey:UserID"`
+	Lesson      lessonV1   `gorm:"foreignKey:LessonID"`
+	Exercise    exerciseV1 `gorm:"foreignKey:ExerciseID"`
+}
+
+func (progressV1) TableName() string { return "progresses" }
+
+type vocabularyV1 struct {
+	ID              uint   `gorm:"primarykey"`
+	Key             string `gorm:"index:idx_vocabularies_key"`
+	German          string
+	English         string
+	Category        string
+	Difficulty      int `gorm:"default:1"`
+	PartOfSpeech    string
+	Gender          string
+	Plural          string
+	Past            string
+	Participle      string
+	SeparablePrefix string
+	IPA             string
+	AudioURL        string
+	Example         string
+	Translation     string
+}
+
+func (vocabularyV1) TableName() string { return "vocabularies" }
+
+type reviewItemV1 struct {
+	ID             uint    `gorm:"primarykey"`
+	UserID         uint    `gorm:"uniqueIndex:idx_review_item"`
+	ItemType       string  `gorm:"uniqueIndex:idx_review_item"`
+	ItemID         uint    `gorm:"uniqueIndex:idx_review_item"`
+	EaseFactor     float64 `gorm:"default:2.5"`
+	Interval       int
+	Repetitions    int
+	Lapses         int
+	DueAt          time.Time `gorm:"index:idx_review_items_due_at"`
+	LastReviewedAt time.Time
+}
+
+func (reviewItemV1) TableName() string { return "review_items" }
+
+type userLessonV1 struct {
+	ID               uint `gorm:"primarykey"`
+	UserID           uint `gorm:"uniqueIndex:idx_user_lesson"`
+	LessonID         uint `gorm:"uniqueIndex:idx_user_lesson"`
+	Status           string
+	BestScore        float64
+	Attempts         int
+	FirstCompletedAt *time.Time
+	LastCompletedAt  *time.Time
+	User             userV1   `gorm:"foreignKey:UserID"`
+	Lesson           lessonV1 `gorm:"foreignKey:LessonID"`
+}
+
+func (userLessonV1) TableName() string { return "user_lessons" }
+
+type settingV1 struct {
+	ID     uint   `gorm:"primarykey"`
+	UserID uint   `gorm:"uniqueIndex:idx_setting"`
+	Key    string `gorm:"uniqueIndex:idx_setting"`
+	Value  string
+}
+
+func (settingV1) TableName() string { return "settings" }
+
+// Version 2
+
+type genderAnswerV2 struct {
+	ID           uint `gorm:"primarykey"`
+	UserID       uint `gorm:"index:idx_gender_answers_user_id"`
+	VocabularyID uint
+	Expected     string
+	Given        string
+	CreatedAt    time.Time
+}
+
+func (genderAnswerV2) TableName() string { return "gender_answers" }
+
+// Version 3
+
+type vocabAnswerV3 struct {
+	ID           uint `gorm:"primarykey"`
+	UserID       uint `gorm:"index:idx_vocab_answers_user_id"`
+	VocabularyID uint
+	Mode         string
+	Correct      bool
+	CreatedAt    time.Time
+}
+
+func (vocabAnswerV3) TableName() string { return "vocab_answers" }
+
+// Version 4
+
+type wordStrengthV4 struct {
+	ID           uint `gorm:"primarykey"`
+	UserID       uint `gorm:"uniqueIndex:idx_word_strength"`
+	VocabularyID uint `gorm:"uniqueIndex:idx_word_strength"`
+	Strength     float64
+	Answers      int
+	Correct      int
+	LastSeenAt   time.Time
+}
+
+func (wordStrengthV4) TableName() string { return "word_strengths" }
+
+// Version 6
+
+type exerciseV6 struct {
+	Parameter string
+}
+
+func (exerciseV6) TableName() string { return "exercises" }
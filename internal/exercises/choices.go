@@ -0,0 +1,206 @@
+package exercises
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/settings"
+	"duocli/internal/store"
+	"encoding/json"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// choicePrefs holds the multiple choice settings of the learner in session
+var choicePrefs = struct {
+	Count   int  // Options shown, including the answer
+	Letters bool // Label options a, b, c instead of 1, 2, 3
+}{Count: 4}
+
+// loadChoicePrefs reads the learner's multiple choice settings
+func loadChoicePrefs(s store.SettingStore, userID uint) {
+	choicePrefs.Count = settings.Int(s, userID, settings.Choices)
+	choicePrefs.Letters = settings.GetOrDefault(s, userID, settings.ChoiceLabels) == "letters"
+}
+
+// choiceOptions returns the shuffled options for a multiple choice exercise.
+// Explicit options are trimmed to the configured count, keeping the answer;
+// missing ones are filled with distractors drawn from the vocabulary.
+func choiceOptions(s store.VocabularyStore, exercise models.Exercise) []string {
+	var options []string
+	if exercise.Options != "" {
+		json.Unmarshal([]byte(exercise.Options), &options)
+	}
+
+	accepted := acceptedAnswers(exercise)
+	var answer string
+	var others []string
+	for _, option := range options {
+		if answer == "" && containsFold(accepted, option) {
+			answer = option
+		} else {
+			others = append(others, option)
+		}
+	}
+	if answer == "" {
+		answer = exercise.Answer
+	}
+
+	rand.Shuffle(len(others), func(i, j int) {
+		others[i], others[j] = others[j], others[i]
+	})
+	want := choicePrefs.Count - 1
+	if len(others) > want {
+		others = others[:want]
+	}
+	if len(others) < want {
+		others = append(others, distractors(s, answer, append(accepted, others...), want-len(others))...)
+	}
+
+	options = append(others, answer)
+	rand.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+	return options
+}
+
+// distractors picks up to n wrong options for answer from the vocabulary:
+// words of the same category, part of speech and similar difficulty first,
+// never a synonym of the answer or anything in exclude
+func distractors(s store.VocabularyStore, answer string, exclude []string, n int) []string {
+	words, _ := s.Words(store.WordQuery{})
+
+	// Find the word the answer comes from, and which side of it to show
+	var target models.Vocabulary
+	german, found := false, false
+	for _, word := range words {
+		if strings.EqualFold(word.English, answer) {
+			target, found = word, true
+			break
+		}
+		if strings.EqualFold(word.WithArticle(), answer) || strings.EqualFold(word.German, answer) {
+			target, found, german = word, true, true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	type candidate struct {
+		value string
+		tier  int
+	}
+	var candidates []candidate
+	seen := map[string]bool{}
+	for _, value := range exclude {
+		seen[strings.ToLower(value)] = true
+	}
+
+	for _, word := range words {
+		if word.ID == target.ID || synonyms(word, target) {
+			continue
+		}
+		value := word.English
+		if german {
+			value = word.WithArticle()
+		}
+		if seen[strings.ToLower(value)] {
+			continue
+		}
+		seen[strings.ToLower(value)] = true
+		candidates = append(candidates, candidate{value, distractorTier(word, target)})
+	}
+
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].tier < candidates[j].tier
+	})
+
+	var picked []string
+	for _, c := range candidates {
+		if len(picked) == n {
+			break
+		}
+		picked = append(picked, c.value)
+	}
+	return picked
+}
+
+// distractorTier ranks how good a distractor word is for target, lower is
+// better
+func distractorTier(word, target models.Vocabulary) int {
+	sameCategory := word.Category == target.Category
+	samePOS := word.PartOfSpeech == target.PartOfSpeech
+	similar := word.Difficulty-target.Difficulty <= 1 && target.Difficulty-word.Difficulty <= 1
+
+	switch {
+	case sameCategory && samePOS && similar:
+		return 0
+	case sameCategory && samePOS:
+		return 1
+	case samePOS && similar:
+		return 2
+	case sameCategory:
+		return 3
+	case samePOS:
+		return 4
+	}
+	return 5
+}
+
+// synonyms reports whether two words share a German form or an English
+// meaning; meanings are separated by slashes, e.g. "she/they"
+func synonyms(a, b models.Vocabulary) bool {
+	if strings.EqualFold(a.German, b.German) {
+		return true
+	}
+	for _, x := range meanings(a.English) {
+		for _, y := range meanings(b.English) {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// meanings splits an English translation into its normalised meanings,
+// without leading articles or "to"
+func meanings(english string) []string {
+	var result []string
+	for _, meaning := range strings.Split(strings.ToLower(english), "/") {
+		meaning = strings.TrimSpace(meaning)
+		for _, prefix := range []string{"the ", "a ", "an ", "to "} {
+			meaning = strings.TrimPrefix(meaning, prefix)
+		}
+		if meaning != "" {
+			result = append(result, meaning)
+		}
+	}
+	return result
+}
+
+// choiceLabel returns the label of the i-th option, 0-based
+func choiceLabel(i int) string {
+	if choicePrefs.Letters {
+		return string(rune('a' + i))
+	}
+	return string(rune('1' + i))
+}
+
+// pickChoice maps an answer to an option: a number, a letter or the option
+// text itself are all accepted
+func pickChoice(answer string, options []string) (string, bool) {
+	answer = strings.TrimSpace(answer)
+	for i, option := range options {
+		if strings.EqualFold(answer, option) {
+			return option, true
+		}
+		if strings.EqualFold(answer, string(rune('1'+i))) || strings.EqualFold(answer, string(rune('a'+i))) {
+			return option, true
+		}
+	}
+	return answer, false
+}
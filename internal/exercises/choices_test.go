@@ -0,0 +1,161 @@
+package exercises
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// seedChoiceWords stores a vocabulary with animals, objects and verbs
+func seedChoiceWords(t *testing.T) *store.Memory {
+	t.Helper()
+	s := store.NewMemory()
+	for _, word := range []models.Vocabulary{
+		{German: "Hund", English: "dog", Category: "animals", PartOfSpeech: "noun", Gender: "der", Difficulty: 1},
+		{German: "Köter", English: "mutt/dog", Category: "animals", PartOfSpeech: "noun", Gender: "der", Difficulty: 3},
+		{German: "Katze", English: "cat", Category: "animals", PartOfSpeech: "noun", Gender: "die", Difficulty: 1},
+		{German: "Pferd", English: "horse", Category: "animals", PartOfSpeech: "noun", Gender: "das", Difficulty: 2},
+		{German: "Elefant", English: "elephant", Category: "animals", PartOfSpeech: "noun", Gender: "der", Difficulty: 4},
+		{German: "Haus", English: "house", Category: "objects", PartOfSpeech: "noun", Gender: "das", Difficulty: 1},
+		{German: "essen", English: "to eat", Category: "verbs", PartOfSpeech: "verb", Difficulty: 1},
+	} {
+		if err := s.SaveWord(&word); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestDistractors(t *testing.T) {
+	s := seedChoiceWords(t)
+
+	tests := []struct {
+		answer  string
+		exclude []string
+		n       int
+		want    []string // Compared as a set, as equally good words are shuffled
+	}{
+		// Same category, part of speech and similar difficulty first, then
+		// the same category; never the synonym "mutt/dog"
+		{"dog", nil, 2, []string{"cat", "horse"}},
+		{"dog", nil, 3, []string{"cat", "horse", "elephant"}},
+		{"dog", []string{"Cat"}, 2, []string{"horse", "elephant"}},
+		{"dog", []string{"Cat", "horse", "elephant"}, 2, []string{"house", "to eat"}},
+		{"der Hund", nil, 2, []string{"die Katze", "das Pferd"}},
+		{"Hund", nil, 3, []string{"die Katze", "das Pferd", "der Elefant"}},
+		{"unknown", nil, 3, nil},
+	}
+
+	for _, tt := range tests {
+		got := distractors(s, tt.answer, tt.exclude, tt.n)
+		sort.Strings(got)
+		want := append([]string(nil), tt.want...)
+		sort.Strings(want)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("distractors(%q, %v, %d) = %v, want %v", tt.answer, tt.exclude, tt.n, got, want)
+		}
+	}
+
+	// Asking for more than there are returns every usable word once
+	if got := distractors(s, "dog", nil, 10); len(got) != 5 {
+		t.Errorf("distractors(dog, 10) = %v, want the 5 non-synonyms", got)
+	}
+}
+
+func TestDistractorTier(t *testing.T) {
+	target := models.Vocabulary{Category: "animals", PartOfSpeech: "noun", Difficulty: 2}
+
+	tests := []struct {
+		word models.Vocabulary
+		want int
+	}{
+		{models.Vocabulary{Category: "animals", PartOfSpeech: "noun", Difficulty: 3}, 0},
+		{models.Vocabulary{Category: "animals", PartOfSpeech: "noun", Difficulty: 5}, 1},
+		{models.Vocabulary{Category: "objects", PartOfSpeech: "noun", Difficulty: 1}, 2},
+		{models.Vocabulary{Category: "animals", PartOfSpeech: "verb", Difficulty: 2}, 3},
+		{models.Vocabulary{Category: "objects", PartOfSpeech: "noun", Difficulty: 5}, 4},
+		{models.Vocabulary{Category: "verbs", PartOfSpeech: "verb", Difficulty: 2}, 5},
+	}
+	for _, tt := range tests {
+		if got := distractorTier(tt.word, target); got != tt.want {
+			t.Errorf("distractorTier(%+v) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSynonyms(t *testing.T) {
+	tests := []struct {
+		a, b models.Vocabulary
+		want bool
+	}{
+		{models.Vocabulary{German: "sie", English: "she"}, models.Vocabulary{German: "Sie", English: "you (formal)"}, true},
+		{models.Vocabulary{German: "sie", English: "she/they"}, models.Vocabulary{German: "ihr", English: "they"}, true},
+		{models.Vocabulary{German: "essen", English: "to eat"}, models.Vocabulary{German: "fressen", English: "eat"}, true},
+		{models.Vocabulary{German: "Hund", English: "the dog"}, models.Vocabulary{German: "Köter", English: "a dog"}, true},
+		{models.Vocabulary{German: "Hund", English: "dog"}, models.Vocabulary{German: "Katze", English: "cat"}, false},
+	}
+	for _, tt := range tests {
+		if got := synonyms(tt.a, tt.b); got != tt.want {
+			t.Errorf("synonyms(%q, %q) = %v, want %v", tt.a.English, tt.b.English, got, tt.want)
+		}
+	}
+}
+
+func TestChoiceOptions(t *testing.T) {
+	s := seedChoiceWords(t)
+	t.Cleanup(func() { choicePrefs.Count = 4 })
+
+	options, _ := json.Marshal([]string{"cat", "dog", "house", "horse", "elephant"})
+	tests := []struct {
+		name     string
+		exercise models.Exercise
+		count    int
+	}{
+		{"trimmed", models.Exercise{Answer: "dog", Options: string(options)}, 3},
+		{"filled", models.Exercise{Answer: "dog", Options: `["dog", "cat"]`}, 4},
+		{"drawn", models.Exercise{Answer: "der Hund"}, 5},
+	}
+
+	for _, tt := range tests {
+		choicePrefs.Count = tt.count
+		got := choiceOptions(s, tt.exercise)
+		if len(got) != tt.count {
+			t.Errorf("%s: choiceOptions = %v, want %d options", tt.name, got, tt.count)
+		}
+		if !containsFold(got, tt.exercise.Answer) {
+			t.Errorf("%s: choiceOptions = %v, missing the answer %q", tt.name, got, tt.exercise.Answer)
+		}
+		seen := map[string]bool{}
+		for _, option := range got {
+			if seen[strings.ToLower(option)] {
+				t.Errorf("%s: choiceOptions = %v, %q appears twice", tt.name, got, option)
+			}
+			seen[strings.ToLower(option)] = true
+		}
+	}
+}
+
+func TestPickChoice(t *testing.T) {
+	options := []string{"cat", "dog", "house"}
+
+	tests := []struct {
+		answer string
+		want   string
+		ok     bool
+	}{
+		{"2", "dog", true},
+		{" B ", "dog", true},
+		{"House", "house", true},
+		{"4", "4", false},
+		{"horse", "horse", false},
+	}
+	for _, tt := range tests {
+		got, ok := pickChoice(tt.answer, options)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("pickChoice(%q) = %q, %v, want %q, %v", tt.answer, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package exercises
+
+import (
+	"duocli/internal/config"
+	"math"
+	"time"
+)
+
+// cfg holds the pass marks, XP and pacing used by exercises
+var cfg = config.Default()
+
+// Configure sets the configuration exercises run with. It is called once at
+// startup.
+func Configure(c *config.Config) {
+	cfg = c
+}
+
+// pause waits after an answer so feedback can be read. Scripted sessions
+// configure no pause.
+func pause() {
+	if cfg.Pause > 0 {
+		time.Sleep(cfg.Pause)
+	}
+}
+
+// drillXP is the XP for a correct answer in the speed and gender drills.
+// Drill questions are quick, so they earn 40% of a lesson answer, at least 1.
+func drillXP() int {
+	return max(1, int(math.Round(float64(cfg.XPPerAnswer)*0.4)))
+}
@@ -0,0 +1,87 @@
+package exercises
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/grading"
+	"duocli/internal/grammar"
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"math/rand"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// verbCategory is the vocabulary category conjugation exercises draw from
+const verbCategory = "verbs"
+
+// pronouns are the subjects asked for each person in grammar.Persons
+var pronouns = [][]string{
+	{"ich"},
+	{"du"},
+	{"er", "sie", "es"},
+	{"wir"},
+	{"ihr"},
+	{"sie", "Sie"},
+}
+
+// handleConjugation asks for one form of a verb from the exercise's
+// options or the verbs category. The parameter names the tense to ask, or
+// "any" for a random one.
+func handleConjugation(s store.VocabularyStore, exercise models.Exercise) (grading.Result, bool) {
+	tense, ok := grammar.ParseTense(exercise.Parameter)
+	if !ok {
+		tense = grammar.Tenses[rand.Intn(len(grammar.Tenses))]
+	}
+
+	var word models.Vocabulary
+	var conjugation grammar.Conjugation
+	found := false
+	for _, candidate := range exerciseVocabulary(s, exercise, verbCategory) {
+		if c, err := grammar.ConjugateSeparable(candidate.German, candidate.SeparablePrefix); err == nil {
+			word, conjugation, found = candidate, c, true
+			break
+		}
+	}
+	if !found {
+		color.Yellow("⚠️  No verbs to conjugate, skipping.")
+		return grading.Result{Verdict: grading.Skipped}, true
+	}
+
+	person := rand.Intn(len(grammar.Persons))
+	pronoun := pronouns[person][rand.Intn(len(pronouns[person]))]
+	form := conjugation.Forms[tense][person]
+
+	console.Printf("🔧 %s (%s) · %s\n", conjugation.Infinitive, word.English, tense.Title())
+	console.Printf("   %s ___\n", pronounLabel(person, pronoun))
+	console.Print("Your answer: ")
+	answer, ok := console.ReadLine()
+	if !ok {
+		return grading.Result{}, false
+	}
+
+	// The pronoun may be typed along with the verb
+	given := strings.TrimSpace(answer)
+	if fields := strings.Fields(given); len(fields) > 1 && strings.EqualFold(fields[0], pronoun) {
+		given = strings.Join(fields[1:], " ")
+	}
+
+	result := grading.Result{Given: answer, Expected: pronoun + " " + form}
+	if grading.Normalize(given) == grading.Normalize(form) {
+		result.Verdict = grading.Correct
+	}
+	return result, true
+}
+
+// pronounLabel tells the two kinds of "sie" apart in prompts
+func pronounLabel(person int, pronoun string) string {
+	switch {
+	case pronoun == "sie" && person == 2:
+		return "sie (she)"
+	case pronoun == "sie":
+		return "sie (they)"
+	case pronoun == "Sie":
+		return "Sie (formal)"
+	}
+	return pronoun
+}
@@ -0,0 +1,97 @@
+package exercises
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/grading"
+	"duocli/internal/grammar"
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// caseSentences frame a noun phrase so that it takes the given case
+var caseSentences = map[grammar.Case][]string{
+	grammar.Nominative: {"%s ist hier.", "Wo ist %s?"},
+	grammar.Accusative: {"Ich sehe %s.", "Wir brauchen %s."},
+	grammar.Dative:     {"Ich spreche mit %s.", "Wir wohnen bei %s."},
+	grammar.Genitive:   {"Das ist die Farbe %s.", "Wir sprechen wegen %s."},
+}
+
+// declensionAdjectives are used for adjective ending questions
+var declensionAdjectives = []string{"klein", "groß", "alt", "neu", "gut", "schön"}
+
+// handleDeclension asks for an article or adjective ending in a sentence
+// built around a noun from the exercise's options or the whole vocabulary.
+// The parameter names the case to ask, or "any" for a random one.
+func handleDeclension(s store.VocabularyStore, exercise models.Exercise) (grading.Result, bool) {
+	c, ok := grammar.ParseCase(exercise.Parameter)
+	if !ok {
+		c = grammar.Cases[rand.Intn(len(grammar.Cases))]
+	}
+
+	var gender, noun string
+	found := false
+	for _, word := range exerciseVocabulary(s, exercise, "") {
+		if word.Gender != "" {
+			gender, noun, found = word.Gender, word.German, true
+			break
+		}
+	}
+	if !found {
+		color.Yellow("⚠️  No nouns to decline, skipping.")
+		return grading.Result{Verdict: grading.Skipped}, true
+	}
+
+	kind := grammar.Definite
+	if rand.Intn(2) == 0 {
+		kind = grammar.Indefinite
+	}
+	article := grammar.Article(kind, c, gender)
+	nounForm := grammar.NounForm(noun, c, gender)
+
+	// Half the questions ask for the article, half for an adjective ending
+	var blank, hint, phrase string
+	var accepted []string
+	if rand.Intn(2) == 0 {
+		blank = "___ " + nounForm
+		hint = "definite"
+		if kind == grammar.Indefinite {
+			hint = "indefinite"
+		}
+		accepted = []string{article, article + " " + nounForm}
+		phrase = grammar.Phrase(kind, c, gender, "", noun)
+	} else {
+		adjective := declensionAdjectives[rand.Intn(len(declensionAdjectives))]
+		ending := grammar.AdjectiveEnding(kind, c, gender)
+		blank = fmt.Sprintf("%s %s___ %s", article, adjective, nounForm)
+		hint = "ending"
+		accepted = []string{ending, adjective + ending}
+		phrase = grammar.Phrase(kind, c, gender, adjective, noun)
+	}
+
+	templates := caseSentences[c]
+	sentence := fmt.Sprintf(templates[rand.Intn(len(templates))], blank)
+	sentence = strings.ToUpper(sentence[:1]) + sentence[1:]
+
+	console.Printf("📐 %s (%s, %s)\n", sentence, c.Abbrev(), hint)
+	console.Print("Fill in the blank: ")
+	answer, ok := console.ReadLine()
+	if !ok {
+		return grading.Result{}, false
+	}
+
+	result := grading.Result{Given: answer, Expected: accepted[0]}
+	for _, want := range accepted {
+		if grading.Normalize(answer) == grading.Normalize(want) {
+			result.Verdict = grading.Correct
+		}
+	}
+	if result.Verdict != grading.Correct {
+		result.Note = phrase
+	}
+	return result, true
+}
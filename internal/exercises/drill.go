@@ -0,0 +1,291 @@
+package exercises
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/grading"
+	"duocli/internal/models"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// DrillOptions configures a timed speed drill
+type DrillOptions struct {
+	Count        int           // Maximum number of questions
+	QuestionTime time.Duration // Time allowed per question
+	SessionTime  time.Duration // Time allowed for the whole drill
+}
+
+// Validate reports options a drill can't run with: without time on the
+// clock every question would time out before it could be answered
+func (o DrillOptions) Validate() error {
+	if o.QuestionTime <= 0 {
+		return fmt.Errorf("the time per question must be positive, got %s", o.QuestionTime)
+	}
+	if o.SessionTime <= 0 {
+		return fmt.Errorf("the drill duration must be positive, got %s", o.SessionTime)
+	}
+	return nil
+}
+
+// drillItem is a single typed question in a drill
+type drillItem struct {
+	itemType string // review item type, used to feed the scheduler
+	itemID   uint
+	question string
+	accepted []string
+}
+
+// Speed bonus rules: enough correct answers at a fast enough pace earn one
+// XP per correct answer per minute, never more than the drill itself earned
+const (
+	speedBonusAnswers = 5
+	speedBonusPace    = 10
+)
+
+// DrillSession tracks the score of a speed drill
+type DrillSession struct {
+	Asked        int
+	Correct      int
+	TimedOut     int
+	XPEarned     int
+	Elapsed      time.Duration
+	Answering    time.Duration // Total time spent on questions, answered or not
+	ResponseTime time.Duration // Total time spent on correct answers
+}
+
+// AnswersPerMinute returns correct answers per minute spent answering
+func (s *DrillSession) AnswersPerMinute() float64 {
+	if s.Answering <= 0 {
+		return 0
+	}
+	return float64(s.Correct) / s.Answering.Minutes()
+}
+
+// SpeedBonus returns the bonus XP the drill's pace earns
+func (s *DrillSession) SpeedBonus() int {
+	apm := s.AnswersPerMinute()
+	if s.Correct < speedBonusAnswers || apm < speedBonusPace {
+		return 0
+	}
+	if apm > float64(s.XPEarned) {
+		return s.XPEarned
+	}
+	return int(apm)
+}
+
+// StartDrill runs a timed drill over vocabulary and exercises from lessons
+// the user has completed
+func StartDrill(s store.Store, userID uint, opts DrillOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	items := drillItems(s, userID)
+	if len(items) == 0 {
+		return fmt.Errorf("nothing to drill yet")
+	}
+
+	rand.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+	if opts.Count > 0 && len(items) > opts.Count {
+		items = items[:opts.Count]
+	}
+
+	color.Cyan("\n⚡ Speed Drill")
+	color.Yellow("⏱️  %d questions, %s per question, %s in total",
+		len(items), opts.QuestionTime, opts.SessionTime)
+	color.White("Type your answer and press Enter before the clock runs out.")
+
+	session := &DrillSession{}
+	words := sessionWords{}
+	start := time.Now()
+	deadline := start.Add(opts.SessionTime)
+
+	for i, item := range items {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			color.Red("\n⏰ Time's up!")
+			break
+		}
+
+		limit := opts.QuestionTime
+		if remaining < limit {
+			limit = remaining
+		}
+
+		color.Blue("\n⚡ %d/%d  ⏱️  %ds  🕐 %s left", i+1, len(items), int(limit.Seconds()), formatClock(remaining))
+		console.Printf("%s\n> ", item.question)
+
+		asked := time.Now()
+		answer, ok, ended := readDrillAnswer(asked, limit)
+		if ended {
+			// Nobody is left to answer, so nothing more is graded
+			console.Println()
+			color.Yellow("📭 No more answers; the drill ends here.")
+			break
+		}
+		taken := time.Since(asked)
+		session.Asked++
+		session.Answering += taken
+
+		if !ok {
+			session.TimedOut++
+			console.Println()
+			color.Red("⏰ Too slow! The answer was: %s", item.accepted[0])
+			if err := recordItem(s, userID, words, item.itemType, item.itemID, review.QualityWrong); err != nil {
+				return fmt.Errorf("failed to save answer: %w", err)
+			}
+			continue
+		}
+
+		result := grading.GradeAny(answer, item.accepted)
+		if !result.Verdict.Accepted() {
+			color.Red("❌ The answer was: %s", item.accepted[0])
+			if err := recordItem(s, userID, words, item.itemType, item.itemID, review.QualityWrong); err != nil {
+				return fmt.Errorf("failed to save answer: %w", err)
+			}
+			continue
+		}
+
+		session.Correct++
+		session.ResponseTime += taken
+
+		xp := drillXP()
+		if taken <= limit/3 {
+			xp *= 2
+			color.Green("⚡ Lightning fast! (+%d XP)", xp)
+		} else {
+			color.Green("✅ Correct! (+%d XP)", xp)
+		}
+		if result.Verdict == grading.Typo {
+			color.Yellow("   %s (%s)", result.Note, result.Expected)
+		}
+		session.XPEarned += xp
+		if err := recordItem(s, userID, words, item.itemType, item.itemID, reviewQuality(result.Verdict)); err != nil {
+			return fmt.Errorf("failed to save answer: %w", err)
+		}
+	}
+
+	session.Elapsed = time.Since(start)
+
+	// A question that timed out is still waiting on its line of input
+	if console.Pending() {
+		console.Print("\nPress Enter to see your results...")
+		console.ReadLine()
+	}
+
+	// Bonus XP for a fast pace
+	if bonus := session.SpeedBonus(); bonus > 0 {
+		session.XPEarned += bonus
+		color.Green("🎉 Speed bonus: +%d XP", bonus)
+	}
+
+	if err := words.save(s, userID); err != nil {
+		return fmt.Errorf("failed to save word strengths: %w", err)
+	}
+
+	user, err := s.User(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if err := awardXP(s, &user, session.XPEarned); err != nil {
+		return err
+	}
+
+	showDrillResults(session)
+	return nil
+}
+
+// readDrillAnswer waits for an answer typed after the question was asked,
+// skipping late answers to earlier questions. ended reports that input ran
+// out before an answer came.
+func readDrillAnswer(asked time.Time, limit time.Duration) (answer string, ok, ended bool) {
+	deadline := asked.Add(limit)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return "", false, false
+		}
+
+		line, ok := console.ReadLineTimeout(remaining)
+		if !ok {
+			return "", false, line.EOF
+		}
+		if line.At.Before(asked) {
+			continue
+		}
+		return line.Text, true, false
+	}
+}
+
+// drillItems collects typed questions from vocabulary and from exercises in
+// lessons the user has completed
+func drillItems(s store.Store, userID uint) []drillItem {
+	var items []drillItem
+
+	vocab, _ := s.Words(store.WordQuery{})
+	for _, word := range vocab {
+		items = append(items, drillItem{
+			itemType: review.ItemVocabulary,
+			itemID:   word.ID,
+			question: fmt.Sprintf("🇺🇸 → 🇩🇪  %s", word.English),
+			accepted: []string{word.WithArticle()},
+		})
+	}
+
+	states, _ := s.LessonStates(userID)
+	for _, state := range states {
+		if state.Status != models.LessonCompleted {
+			continue
+		}
+		exercises, _ := s.LessonExercises(state.LessonID)
+		for _, exercise := range exercises {
+			if exercise.Type != "translation" && exercise.Type != "fill_blank" {
+				continue
+			}
+			items = append(items, drillItem{
+				itemType: review.ItemExercise,
+				itemID:   exercise.ID,
+				question: exercise.Question,
+				accepted: acceptedAnswers(exercise),
+			})
+		}
+	}
+
+	return items
+}
+
+func showDrillResults(session *DrillSession) {
+	console.Println("\n" + strings.Repeat("=", 50))
+	color.Cyan("⚡ DRILL COMPLETE!")
+	console.Println(strings.Repeat("=", 50))
+
+	accuracy := float64(0)
+	if session.Asked > 0 {
+		accuracy = float64(session.Correct) / float64(session.Asked) * 100
+	}
+
+	color.White("Correct: %d/%d (%.1f%%)", session.Correct, session.Asked, accuracy)
+	color.White("Timed Out: %d", session.TimedOut)
+	color.White("Time: %s", formatClock(session.Elapsed))
+	color.Yellow("Pace: %.1f correct answers per minute", session.AnswersPerMinute())
+	if session.Correct > 0 {
+		avg := session.ResponseTime / time.Duration(session.Correct)
+		color.Yellow("Average Response: %.1fs", avg.Seconds())
+	}
+	color.Green("XP Earned: +%d", session.XPEarned)
+
+	console.Println(strings.Repeat("=", 50))
+}
+
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
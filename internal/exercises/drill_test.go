@@ -0,0 +1,59 @@
+package exercises
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"testing"
+	"time"
+)
+
+func TestDrillOptionsValidate(t *testing.T) {
+	tests := []struct {
+		question, session time.Duration
+		ok                bool
+	}{
+		{10 * time.Second, 2 * time.Minute, true},
+		{0, 2 * time.Minute, false},
+		{10 * time.Second, 0, false},
+		{-time.Second, 2 * time.Minute, false},
+		{10 * time.Second, -time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		err := DrillOptions{QuestionTime: tt.question, SessionTime: tt.session}.Validate()
+		if (err == nil) != tt.ok {
+			t.Errorf("Validate(%s, %s) = %v, want ok %v", tt.question, tt.session, err, tt.ok)
+		}
+	}
+}
+
+func TestStartDrillEndOfInput(t *testing.T) {
+	s := store.NewMemory()
+	user := models.User{Name: "tester"}
+	if err := s.CreateUser(&user); err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []models.Vocabulary{
+		{German: "Hund", English: "dog", Gender: "der"},
+		{German: "Katze", English: "cat", Gender: "die"},
+		{German: "Haus", English: "house", Gender: "das"},
+	} {
+		if err := s.SaveWord(&word); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One wrong answer, then input runs out with two questions left
+	scriptLesson(t, "falsch\n")
+	if err := StartDrill(s, user.ID, DrillOptions{QuestionTime: 10 * time.Second, SessionTime: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+
+	items, _ := s.DueReviewItems(user.ID, time.Now().AddDate(1, 0, 0), 0)
+	if len(items) != 1 || items[0].Lapses != 1 {
+		t.Errorf("got %d review items %+v, want only the answered one", len(items), items)
+	}
+	if rows, _ := s.WordStrengths(user.ID); len(rows) != 1 {
+		t.Errorf("got %d strength rows, want 1", len(rows))
+	}
+}
@@ -1,13 +1,19 @@
 package exercises
 
 import (
-	"bufio"
-	"duocli/internal/database"
+	"duocli/internal/console"
+	"duocli/internal/grading"
+	"duocli/internal/hearts"
 	"duocli/internal/models"
+	"duocli/internal/progress"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"duocli/internal/ui"
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/rand"
-	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -16,23 +22,38 @@ import (
 )
 
 type ExerciseSession struct {
-	UserID   uint
-	LessonID uint
-	Score    int
-	Total    int
-	XPEarned int
+	UserID          uint
+	LessonID        uint
+	Score           int // Exercises eventually answered correctly
+	FirstTryCorrect int // Exercises answered correctly on the first try
+	Attempts        int // Answers given, including retries
+	Total           int
+	XPEarned        int
+	OutOfHearts     bool // The lesson ended early because hearts ran out
 }
 
-func StartLesson(userID, lessonID uint) error {
+// FirstTryAccuracy returns the percentage of exercises answered correctly
+// on the first try
+func (s *ExerciseSession) FirstTryAccuracy() float64 {
+	return float64(s.FirstTryCorrect) / float64(s.Total) * 100
+}
+
+// EventualAccuracy returns the percentage of exercises answered correctly
+// once retries are counted
+func (s *ExerciseSession) EventualAccuracy() float64 {
+	return float64(s.Score) / float64(s.Total) * 100
+}
+
+func StartLesson(s store.Store, userID, lessonID uint) error {
 	// Get lesson details
-	var lesson models.Lesson
-	if err := database.DB.First(&lesson, lessonID).Error; err != nil {
+	lesson, err := s.Lesson(lessonID)
+	if err != nil {
 		return fmt.Errorf("lesson not found: %w", err)
 	}
 
 	// Get exercises for this lesson
-	var exercises []models.Exercise
-	if err := database.DB.Where("lesson_id = ?", lessonID).Order("\"order\"").Find(&exercises).Error; err != nil {
+	exercises, err := s.LessonExercises(lessonID)
+	if err != nil {
 		return fmt.Errorf("failed to load exercises: %w", err)
 	}
 
@@ -40,6 +61,23 @@ func StartLesson(userID, lessonID uint) error {
 		return fmt.Errorf("no exercises found for this lesson")
 	}
 
+	user, err := s.User(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	loadChoicePrefs(s, userID)
+
+	// Check hearts before starting
+	useHearts := hearts.Enabled(s, userID)
+	if useHearts {
+		hearts.Refresh(&user, time.Now())
+		if user.Hearts == 0 {
+			color.Red("💔 You're out of hearts! Next heart in %s.", hearts.FormatWait(hearts.NextIn(user, time.Now())))
+			color.Yellow("🔁 Practise with a review session to earn hearts back.")
+			return nil
+		}
+	}
+
 	session := &ExerciseSession{
 		UserID:   userID,
 		LessonID: lessonID,
@@ -51,79 +89,349 @@ func StartLesson(userID, lessonID uint) error {
 	color.Cyan("\n🎓 Starting Lesson: %s", lesson.Title)
 	color.White("📝 %s", lesson.Description)
 	color.Yellow("💪 %d exercises to complete\n", len(exercises))
+	if useHearts {
+		color.Red("%s", hearts.Display(user.Hearts))
+	}
 
-	// Run through exercises
-	for i, exercise := range exercises {
-		color.Blue("\n📚 Exercise %d/%d", i+1, len(exercises))
-		
-		correct := runExercise(exercise)
-		if correct {
-			session.Score++
-			session.XPEarned += 5
-			color.Green("✅ Correct! (+5 XP)")
+	emit(TranscriptEvent{Event: "lesson_start", LessonID: lessonID, Total: len(exercises)})
+
+	// Ctrl-C asks whether to keep what has been answered so far
+	stopCatching := console.CatchInterrupts()
+	defer stopCatching()
+
+	// Run through exercises, re-queueing missed ones at the end until they
+	// are answered correctly, hearts run out or the learner stops. Results
+	// are kept back until the lesson ends so it is saved all at once.
+	queue := append([]models.Exercise(nil), exercises...)
+	attempts := map[uint]int{}
+	firstVerdicts := map[uint]grading.Verdict{}
+	var finished []finishedExercise
+	seen := 0
+
+	for len(queue) > 0 {
+		exercise := queue[0]
+		queue = queue[1:]
+
+		attempts[exercise.ID]++
+		session.Attempts++
+		if attempts[exercise.ID] == 1 {
+			seen++
+			color.Blue("\n📚 Exercise %d/%d", seen, len(exercises))
 		} else {
-			color.Red("❌ Incorrect. The correct answer was: %s", exercise.Answer)
-			if exercise.Explanation != "" {
-				color.Yellow("💡 %s", exercise.Explanation)
+			color.Blue("\n🔁 Let's try this one again (attempt %d)", attempts[exercise.ID])
+		}
+
+		result, answered := runExercise(s, exercise)
+		if console.Interrupted() {
+			return interruptLesson(s, &user, session, finished)
+		}
+		// Running out of input abandons the lesson, since the unanswered
+		// exercises would otherwise all be graded wrong
+		if !answered {
+			emit(TranscriptEvent{Event: "lesson_abandoned", LessonID: lessonID, Total: session.Total})
+			color.Yellow("\n⏹️  Input ended before the lesson did, your answers were not saved.")
+			return nil
+		}
+		// An exercise that can't be asked is left out of the lesson
+		if result.Verdict == grading.Skipped {
+			session.Total--
+			session.Attempts--
+			continue
+		}
+		correct := result.Verdict.Accepted()
+		xp := showVerdict(result, exercise)
+		session.XPEarned += xp
+		emitAnswer(exercise, result, attempts[exercise.ID], xp)
+
+		// Every miss costs a heart, saved straight away
+		if useHearts && !correct {
+			if err := hearts.Lose(s, &user); err != nil {
+				return fmt.Errorf("failed to save hearts: %w", err)
+			}
+			color.Red("💔 %s", hearts.Display(user.Hearts))
+			if user.Hearts == 0 {
+				session.OutOfHearts = true
 			}
 		}
 
-		// Record progress
-		progress := models.Progress{
-			UserID:      userID,
-			LessonID:    lessonID,
-			ExerciseID:  exercise.ID,
-			IsCorrect:   correct,
-			Attempts:    1,
-			CompletedAt: time.Now(),
+		if attempts[exercise.ID] == 1 {
+			firstVerdicts[exercise.ID] = result.Verdict
+			if correct {
+				session.FirstTryCorrect++
+			}
+		}
+
+		// Matching pairs are not asked again: each word has been graded and
+		// paid for already, and a retry could match different words
+		if !correct && result.Pairs == nil && !session.OutOfHearts {
+			color.Yellow("🔁 You'll see this one again before the end of the lesson.")
+			queue = append(queue, exercise)
+			pause()
+			continue
+		}
+
+		if correct {
+			session.Score++
+		}
+
+		finished = append(finished, finishedExercise{
+			exercise: exercise,
+			result:   result,
+			progress: models.Progress{
+				UserID:      userID,
+				LessonID:    lessonID,
+				ExerciseID:  exercise.ID,
+				IsCorrect:   correct,
+				Credit:      credit(result),
+				Attempts:    attempts[exercise.ID],
+				CompletedAt: time.Now(),
+			},
+			// The first try feeds the review scheduler
+			quality: reviewQuality(firstVerdicts[exercise.ID]),
+		})
+
+		if session.OutOfHearts {
+			color.Red("\n💔 You're out of hearts! The lesson ends here.")
+			break
 		}
-		database.DB.Create(&progress)
 
 		// Small delay for better UX
-		time.Sleep(1 * time.Second)
+		pause()
 	}
 
-	// Calculate lesson completion
-	completionPercentage := float64(session.Score) / float64(session.Total) * 100
+	if session.Total == 0 {
+		color.Yellow("⚠️  None of this lesson's exercises could be asked, so nothing was recorded.")
+		return nil
+	}
+
+	// Calculate lesson completion from first-try answers so retries
+	// don't inflate the score
+	completionPercentage := session.FirstTryAccuracy()
 	
 	// Bonus XP for high performance
-	if completionPercentage >= 80 {
+	if completionPercentage >= cfg.BonusMark && !session.OutOfHearts {
 		bonusXP := lesson.XPReward
 		session.XPEarned += bonusXP
 		color.Green("🎉 Great job! Bonus XP: +%d", bonusXP)
 	}
 
-	// Update user stats
-	var user models.User
-	database.DB.First(&user, userID)
-	user.XP += session.XPEarned
-	user.LastSeen = time.Now()
-	
-	// Check for level up
-	newLevel := calculateLevel(user.XP)
-	if newLevel > user.Level {
-		user.Level = newLevel
-		color.Magenta("🚀 LEVEL UP! You are now level %d!", newLevel)
+	// Save everything along with the attempt, marking the lesson completed
+	// if the score is good enough
+	passed := completionPercentage >= cfg.PassMark && !session.OutOfHearts
+	err = saveLesson(s, &user, session.XPEarned, finished, func(tx store.Store) error {
+		return progress.RecordAttempt(tx, userID, lessonID, completionPercentage, passed)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save the lesson, nothing was recorded: %w", err)
 	}
-	
-	database.DB.Save(&user)
 
-	// Mark lesson as completed if score is good enough
-	if completionPercentage >= 70 {
-		lesson.IsCompleted = true
-		database.DB.Save(&lesson)
-	}
+	emit(TranscriptEvent{
+		Event:      "lesson_end",
+		LessonID:   lessonID,
+		Score:      session.Score,
+		FirstTry:   session.FirstTryCorrect,
+		Total:      session.Total,
+		Percentage: completionPercentage,
+		XP:         session.XPEarned,
+		Passed:     passed,
+	})
 
 	// Show results
-	showResults(session, completionPercentage)
+	showResults(s, session, completionPercentage)
 	
 	return nil
 }
 
-func runExercise(exercise models.Exercise) bool {
-	fmt.Printf("\n%s\n", exercise.Question)
+// finishedExercise is an exercise the lesson is done with, waiting to be
+// saved when the lesson ends
+type finishedExercise struct {
+	exercise models.Exercise
+	result   grading.Result
+	progress models.Progress
+	quality  int // Review quality of the first try
+}
+
+// saveLesson writes the finished exercises, the XP earned and anything else
+// record writes in one transaction, so a lesson is saved completely or not
+// at all
+func saveLesson(s store.Store, user *models.User, xp int, finished []finishedExercise, record func(tx store.Store) error) error {
+	saved := *user
+	levelUp := addXP(&saved, xp)
+
+	err := s.Transaction(func(tx store.Store) error {
+		// Words practised by several exercises are scheduled once, with the
+		// worst quality they were answered with
+		words := map[uint]int{}
+		strengths := sessionWords{}
+		practised := func(wordID uint, quality int, correct bool) {
+			if worst, ok := words[wordID]; !ok || quality < worst {
+				words[wordID] = quality
+			}
+			strengths.note(wordID, correct)
+		}
+
+		for _, f := range finished {
+			userID := f.progress.UserID
+			if err := tx.AddProgress(&f.progress); err != nil {
+				return err
+			}
+			if err := review.Record(tx, userID, review.ItemExercise, f.exercise.ID, f.quality); err != nil {
+				return err
+			}
+
+			// Matching pairs grade each word on its own
+			if f.result.Pairs != nil {
+				for wordID, correct := range f.result.Pairs {
+					practised(wordID, pairQuality(correct), correct)
+				}
+				continue
+			}
+
+			ids, err := review.PractisedWords(tx, f.exercise)
+			if err != nil {
+				return err
+			}
+			for _, wordID := range ids {
+				practised(wordID, f.quality, f.progress.IsCorrect)
+			}
+		}
+
+		ids := make([]uint, 0, len(words))
+		for wordID := range words {
+			ids = append(ids, wordID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, wordID := range ids {
+			if err := review.Record(tx, user.ID, review.ItemVocabulary, wordID, words[wordID]); err != nil {
+				return err
+			}
+		}
+		if err := strengths.save(tx, user.ID); err != nil {
+			return err
+		}
+
+		if err := tx.SaveUser(&saved); err != nil {
+			return err
+		}
+		if record != nil {
+			return record(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*user = saved
+	if levelUp {
+		color.Magenta("🚀 LEVEL UP! You are now level %d!", user.Level)
+	}
+	return nil
+}
+
+// interruptLesson asks whether to keep the exercises finished before Ctrl-C
+// was pressed. A partial save keeps their answers and XP but does not count
+// as an attempt at the lesson.
+func interruptLesson(s store.Store, user *models.User, session *ExerciseSession, finished []finishedExercise) error {
+	console.Println()
+	if len(finished) == 0 && session.XPEarned == 0 {
+		color.Yellow("⏹️  Lesson abandoned, nothing to save.")
+		return nil
+	}
+
+	color.Yellow("⏸️  Lesson interrupted after %d of %d exercises (+%d XP).", len(finished), session.Total, session.XPEarned)
+	for {
+		console.Print("Save partial progress or abandon? (s/a): ")
+		choice, ok := console.ReadLine()
+		if console.Interrupted() || !ok {
+			choice = "a"
+		}
+
+		switch strings.ToLower(choice) {
+		case "s", "save":
+			if err := saveLesson(s, user, session.XPEarned, finished, nil); err != nil {
+				return fmt.Errorf("failed to save partial progress, nothing was recorded: %w", err)
+			}
+			emit(TranscriptEvent{
+				Event:    "lesson_saved",
+				LessonID: session.LessonID,
+				Score:    session.Score,
+				Total:    session.Total,
+				XP:       session.XPEarned,
+			})
+			color.Green("💾 Saved %d exercises and %d XP. Finish the lesson to complete it.", len(finished), session.XPEarned)
+			return nil
+		case "a", "abandon":
+			emit(TranscriptEvent{Event: "lesson_abandoned", LessonID: session.LessonID, Total: session.Total})
+			color.Yellow("⏹️  Lesson abandoned, your answers were not saved.")
+			return nil
+		}
+	}
+}
+
+// showVerdict prints feedback for a graded answer and returns the XP it earns
+func showVerdict(result grading.Result, exercise models.Exercise) int {
+	switch result.Verdict {
+	case grading.Correct:
+		color.Green("✅ Correct! (+%d XP)", cfg.XPPerAnswer)
+		return cfg.XPPerAnswer
+	case grading.Typo:
+		// Typos earn three fifths of a correct answer
+		xp := int(math.Round(float64(cfg.XPPerAnswer) * 0.6))
+		color.Yellow("✅ %s The correct spelling is: %s (+%d XP)", result.Note, result.Expected, xp)
+		return xp
+	case grading.WrongArticle:
+		color.Red("❌ %s (%s)", result.Note, result.Expected)
+	default:
+		switch {
+		case result.Pairs != nil:
+			xp := int(math.Round(result.Credit * float64(cfg.XPPerAnswer)))
+			color.Yellow("🧩 %s (+%d XP)", result.Note, xp)
+			return xp
+		case exercise.Type == "conjugation":
+			color.Red("❌ Incorrect. The correct form was: %s", result.Expected)
+		case exercise.Type == "declension":
+			color.Red("❌ Incorrect. The correct answer was: %s (%s)", result.Expected, result.Note)
+		case result.Misplaced > 0:
+			color.Red("❌ %s", highlightWord(grading.Tokens(result.Given), result.Misplaced))
+			color.Red("   %s. The correct order was: %s", result.Note, result.Expected)
+		default:
+			color.Red("❌ Incorrect. The correct answer was: %s", exercise.Answer)
+		}
+	}
+
+	if exercise.Explanation != "" {
+		color.Yellow("💡 %s", exercise.Explanation)
+	}
+	return 0
+}
+
+// credit returns the share of an answer that counts towards progress
+func credit(result grading.Result) float64 {
+	if result.Verdict.Accepted() {
+		return 1
+	}
+	return result.Credit
+}
+
+// reviewQuality maps a verdict onto the scheduler's answer quality scale
+func reviewQuality(verdict grading.Verdict) int {
+	switch verdict {
+	case grading.Correct:
+		return review.QualityCorrect
+	case grading.Typo:
+		return review.QualityHard
+	default:
+		return review.QualityWrong
+	}
+}
+
+// runExercise asks an exercise and grades the answer. It returns false when
+// input ran out or Ctrl-C was pressed before an answer was given.
+func runExercise(s store.Store, exercise models.Exercise) (grading.Result, bool) {
+	console.Printf("\n%s\n", exercise.Question)
 	
-	if exercise.Hint != "" {
+	if cfg.ShowHints && exercise.Hint != "" {
 		color.Yellow("💡 Hint: %s", exercise.Hint)
 	}
 
@@ -131,82 +439,226 @@ func runExercise(exercise models.Exercise) bool {
 	case "translation":
 		return handleTranslation(exercise)
 	case "multiple_choice":
-		return handleMultipleChoice(exercise)
+		return handleMultipleChoice(s, exercise)
 	case "fill_blank":
 		return handleFillBlank(exercise)
+	case "word_order":
+		return handleWordOrder(exercise)
+	case "match_pairs":
+		return handleMatchPairs(s, exercise)
+	case "conjugation":
+		return handleConjugation(s, exercise)
+	case "declension":
+		return handleDeclension(s, exercise)
 	default:
 		return handleTranslation(exercise)
 	}
 }
 
-func handleTranslation(exercise models.Exercise) bool {
-	fmt.Print("Your answer: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	answer := strings.TrimSpace(scanner.Text())
+func handleTranslation(exercise models.Exercise) (grading.Result, bool) {
+	console.Print("Your answer: ")
+	answer, ok := console.ReadLine()
+	if !ok {
+		return grading.Result{}, false
+	}
 	
-	return strings.EqualFold(answer, exercise.Answer)
+	return grading.GradeAny(answer, acceptedAnswers(exercise)), true
 }
 
-func handleMultipleChoice(exercise models.Exercise) bool {
-	var options []string
-	json.Unmarshal([]byte(exercise.Options), &options)
-	
-	// Shuffle options
-	rand.Shuffle(len(options), func(i, j int) {
-		options[i], options[j] = options[j], options[i]
-	})
+func handleMultipleChoice(s store.Store, exercise models.Exercise) (grading.Result, bool) {
+	options := choiceOptions(s, exercise)
+	if len(options) < 2 {
+		color.Yellow("⚠️  This exercise has no usable options, type your answer instead.")
+		return handleTranslation(exercise)
+	}
 	
-	fmt.Println("\nChoose the correct answer:")
+	console.Println("\nChoose the correct answer:")
 	for i, option := range options {
-		fmt.Printf("%d. %s\n", i+1, option)
+		console.Printf("%s. %s\n", choiceLabel(i), option)
 	}
 	
-	fmt.Print("Your choice (1-4): ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	choice := strings.TrimSpace(scanner.Text())
+	console.Printf("Your choice (%s-%s): ", choiceLabel(0), choiceLabel(len(options)-1))
+	choice, ok := console.ReadLine()
+	if !ok {
+		return grading.Result{}, false
+	}
 	
-	choiceNum, err := strconv.Atoi(choice)
-	if err != nil || choiceNum < 1 || choiceNum > len(options) {
-		return false
+	result := grading.Result{Given: choice, Expected: exercise.Answer}
+	given, ok := pickChoice(choice, options)
+	if !ok {
+		return result, true
 	}
+	result.Given = given
 	
-	selectedAnswer := options[choiceNum-1]
-	return strings.EqualFold(selectedAnswer, exercise.Answer)
+	for _, answer := range acceptedAnswers(exercise) {
+		if strings.EqualFold(result.Given, answer) {
+			result.Verdict = grading.Correct
+		}
+	}
+	return result, true
 }
 
-func handleFillBlank(exercise models.Exercise) bool {
-	fmt.Print("Fill in the blank: ")
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	answer := strings.TrimSpace(scanner.Text())
+func handleFillBlank(exercise models.Exercise) (grading.Result, bool) {
+	console.Print("Fill in the blank: ")
+	answer, ok := console.ReadLine()
+	if !ok {
+		return grading.Result{}, false
+	}
 	
-	return strings.EqualFold(answer, exercise.Answer)
+	return grading.GradeAny(answer, acceptedAnswers(exercise)), true
 }
 
-func showResults(session *ExerciseSession, percentage float64) {
-	fmt.Println("\n" + strings.Repeat("=", 50))
+func handleWordOrder(exercise models.Exercise) (grading.Result, bool) {
+	tokens := wordOrderTokens(exercise)
+	accepted := acceptedAnswers(exercise)
+
+	// Shuffle until the tokens are out of order, when that is possible
+	shuffled := append([]string(nil), tokens...)
+	for i := 0; i < 10; i++ {
+		rand.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+		if !grading.GradeOrder(shuffled, accepted).Verdict.Accepted() {
+			break
+		}
+	}
+
+	console.Println("\nPut the words in order:")
+	for i, token := range shuffled {
+		console.Printf("  %d. %s\n", i+1, token)
+	}
+
+	console.Print("Your order (numbers like \"2 1 3\" or the sentence): ")
+	answer, ok := console.ReadLine()
+	if !ok {
+		return grading.Result{}, false
+	}
+
+	return grading.GradeOrder(pickTokens(answer, shuffled), accepted), true
+}
+
+// wordOrderTokens returns the tokens to scramble, taken from the options
+// when the content provides them and from the answer otherwise
+func wordOrderTokens(exercise models.Exercise) []string {
+	var tokens []string
+	if exercise.Options != "" {
+		json.Unmarshal([]byte(exercise.Options), &tokens)
+	}
+	if len(tokens) == 0 {
+		tokens = grading.Tokens(exercise.Answer)
+	}
+	return tokens
+}
+
+// pickTokens turns an answer into words. An answer made only of token
+// numbers picks those tokens; anything else is read as the typed sentence.
+func pickTokens(answer string, tokens []string) []string {
+	fields := strings.FieldsFunc(answer, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	if len(fields) == 0 {
+		return nil
+	}
+
+	picked := make([]string, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(tokens) {
+			return grading.Tokens(answer)
+		}
+		picked = append(picked, tokens[n-1])
+	}
+	return picked
+}
+
+// highlightWord renders words with the 1-based position pos underlined
+func highlightWord(words []string, pos int) string {
+	out := make([]string, len(words))
+	for i, word := range words {
+		if i == pos-1 {
+			word = color.New(color.FgHiWhite, color.BgRed, color.Underline).Sprint(word)
+		}
+		out[i] = word
+	}
+	if pos > len(words) {
+		out = append(out, color.New(color.BgRed).Sprint("___"))
+	}
+	return strings.Join(out, " ")
+}
+
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptedAnswers returns the canonical answer followed by any alternatives
+func acceptedAnswers(exercise models.Exercise) []string {
+	answers := []string{exercise.Answer}
+
+	var alternatives []string
+	if exercise.Alternatives != "" {
+		json.Unmarshal([]byte(exercise.Alternatives), &alternatives)
+	}
+
+	return append(answers, alternatives...)
+}
+
+func showResults(s store.Store, session *ExerciseSession, percentage float64) {
+	console.Println("\n" + strings.Repeat("=", 50))
 	color.Cyan("📊 LESSON COMPLETE!")
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 	
-	color.White("Score: %d/%d (%.1f%%)", session.Score, session.Total, percentage)
+	color.White("First-try Score: %d/%d (%.1f%%)", session.FirstTryCorrect, session.Total, percentage)
+	color.White("After Retries: %d/%d (%.1f%%)", session.Score, session.Total, session.EventualAccuracy())
+	color.White("Answers Given: %d", session.Attempts)
 	color.Green("XP Earned: +%d", session.XPEarned)
 	
-	if percentage >= 90 {
+	if session.OutOfHearts {
+		color.Red("💔 Out of hearts! Review to earn hearts back, then retake this lesson.")
+	} else if percentage >= cfg.PerfectMark {
 		color.Magenta("🏆 PERFECT! Outstanding work!")
-	} else if percentage >= 80 {
+	} else if percentage >= cfg.BonusMark {
 		color.Green("🌟 EXCELLENT! Great job!")
-	} else if percentage >= 70 {
+	} else if percentage >= cfg.PassMark {
 		color.Yellow("👍 GOOD! Lesson completed!")
 	} else {
 		color.Red("📚 Keep practicing! You can retake this lesson.")
 	}
+	ui.ShowDailyGoal(s, session.UserID)
 	
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 }
 
 func calculateLevel(xp int) int {
 	// Simple level calculation: every 100 XP = 1 level
 	return (xp / 100) + 1
+}
+
+// addXP adds xp to the user and levels them up when it is enough, reporting
+// whether they did
+func addXP(user *models.User, xp int) bool {
+	user.XP += xp
+	user.LastSeen = time.Now()
+	level := calculateLevel(user.XP)
+	if level <= user.Level {
+		return false
+	}
+	user.Level = level
+	return true
+}
+
+// awardXP adds xp to the user and saves them, announcing a level up
+func awardXP(s store.UserStore, user *models.User, xp int) error {
+	levelUp := addXP(user, xp)
+	if err := s.SaveUser(user); err != nil {
+		return err
+	}
+	if levelUp {
+		color.Magenta("🚀 LEVEL UP! You are now level %d!", user.Level)
+	}
+	return nil
 }
\ No newline at end of file
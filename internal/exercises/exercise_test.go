@@ -0,0 +1,439 @@
+package exercises
+
+import (
+	"duocli/internal/config"
+	"duocli/internal/console"
+	"duocli/internal/models"
+	"duocli/internal/review"
+	"duocli/internal/settings"
+	"duocli/internal/store"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptLesson runs lessons against the given answers, one per line, with
+// no pause between exercises
+func scriptLesson(t *testing.T, answers string) {
+	t.Helper()
+	c := config.Default()
+	c.Pause = 0
+	Configure(c)
+	console.Use(console.New(strings.NewReader(answers), io.Discard))
+	t.Cleanup(func() { Configure(config.Default()) })
+}
+
+// seedLesson creates a user and a lesson with the given exercises
+func seedLesson(t *testing.T, s *store.Memory, exercises ...models.Exercise) (models.User, models.Lesson) {
+	t.Helper()
+	user := models.User{Name: "tester"}
+	if err := s.CreateUser(&user); err != nil {
+		t.Fatal(err)
+	}
+	lesson := models.Lesson{Title: "Basics", Order: 1, XPReward: 10, Language: "german"}
+	if err := s.SaveLesson(&lesson); err != nil {
+		t.Fatal(err)
+	}
+	for i := range exercises {
+		exercises[i].LessonID = lesson.ID
+		exercises[i].Order = i + 1
+		if err := s.SaveExercise(&exercises[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return user, lesson
+}
+
+func translation(question, answer string) models.Exercise {
+	return models.Exercise{Type: "translation", Question: question, Answer: answer}
+}
+
+func TestStartLessonPasses(t *testing.T) {
+	s := store.NewMemory()
+	hallo := models.Vocabulary{German: "Hallo", English: "Hello"}
+	if err := s.SaveWord(&hallo); err != nil {
+		t.Fatal(err)
+	}
+	greeting := translation("Hallo", "Hello")
+	greeting.Vocabulary = []models.Vocabulary{hallo}
+	user, lesson := seedLesson(t, s,
+		greeting,
+		translation("Danke", "Thank you"),
+		translation("Guten Morgen", "Good morning"),
+	)
+
+	scriptLesson(t, "hello\nthank you\ngood mornin\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two correct answers, a typo and the bonus for a perfect first try
+	saved, _ := s.User(user.ID)
+	if saved.XP != 5+5+3+10 || saved.Hearts != 5 {
+		t.Errorf("user has %d XP and %d hearts, want 23 and 5", saved.XP, saved.Hearts)
+	}
+
+	state, err := s.LessonState(user.ID, lesson.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != models.LessonCompleted || state.BestScore != 100 || state.Attempts != 1 {
+		t.Errorf("lesson state %q, best %.0f, %d attempts; want completed, 100, 1", state.Status, state.BestScore, state.Attempts)
+	}
+
+	rows, _ := s.ProgressSince(user.ID, time.Time{})
+	if len(rows) != 3 {
+		t.Fatalf("got %d progress rows, want 3", len(rows))
+	}
+	for _, row := range rows {
+		if !row.IsCorrect || row.Attempts != 1 {
+			t.Errorf("exercise %d: correct %v after %d attempts, want correct after 1", row.ExerciseID, row.IsCorrect, row.Attempts)
+		}
+	}
+
+	typo, err := s.ReviewItem(user.ID, review.ItemExercise, rows[2].ExerciseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typo.Repetitions != 1 || typo.EaseFactor >= 2.5 {
+		t.Errorf("typo scheduled with %d repetitions and ease %.2f, want 1 and below 2.5", typo.Repetitions, typo.EaseFactor)
+	}
+	if _, err := s.ReviewItem(user.ID, review.ItemVocabulary, hallo.ID); err != nil {
+		t.Errorf("linked word was not scheduled: %v", err)
+	}
+}
+
+func TestStartLessonRetriesMisses(t *testing.T) {
+	s := store.NewMemory()
+	user, lesson := seedLesson(t, s,
+		translation("Hallo", "Hello"),
+		translation("Danke", "Thank you"),
+	)
+
+	// The miss is asked again at the end of the lesson
+	scriptLesson(t, "goodbye\nthank you\nhello\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := s.User(user.ID)
+	if saved.XP != 10 || saved.Hearts != 4 {
+		t.Errorf("user has %d XP and %d hearts, want 10 and 4", saved.XP, saved.Hearts)
+	}
+
+	// Half right on the first try is below the pass mark
+	state, _ := s.LessonState(user.ID, lesson.ID)
+	if state.Status != models.LessonInProgress || state.BestScore != 50 {
+		t.Errorf("lesson state %q, best %.0f; want in_progress, 50", state.Status, state.BestScore)
+	}
+
+	// The retried exercise is the last one finished
+	rows, _ := s.ProgressSince(user.ID, time.Time{})
+	exercises, _ := s.LessonExercises(lesson.ID)
+	if len(rows) != 2 || rows[1].ExerciseID != exercises[0].ID {
+		t.Fatalf("got progress rows %+v, want the first exercise last", rows)
+	}
+
+	missed, _ := s.ReviewItem(user.ID, review.ItemExercise, rows[1].ExerciseID)
+	if rows[1].Attempts != 2 || !rows[1].IsCorrect || missed.Lapses != 1 {
+		t.Errorf("missed exercise: %d attempts, correct %v, %d lapses; want 2, true, 1", rows[1].Attempts, rows[1].IsCorrect, missed.Lapses)
+	}
+}
+
+func TestStartLessonSkipsUnaskable(t *testing.T) {
+	s := store.NewMemory()
+	// No verbs are in the vocabulary, so the conjugation can't be asked
+	user, lesson := seedLesson(t, s,
+		models.Exercise{Type: "conjugation", Question: "Conjugate", Parameter: "present"},
+		translation("Hallo", "Hello"),
+	)
+
+	scriptLesson(t, "hello\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, _ := s.ProgressSince(user.ID, time.Time{})
+	state, _ := s.LessonState(user.ID, lesson.ID)
+	if len(rows) != 1 || state.Status != models.LessonCompleted {
+		t.Errorf("got %d progress rows and state %q, want 1 and completed", len(rows), state.Status)
+	}
+}
+
+func TestStartLessonNothingAskable(t *testing.T) {
+	s := store.NewMemory()
+	user, lesson := seedLesson(t, s,
+		models.Exercise{Type: "conjugation", Question: "Conjugate", Parameter: "present"},
+	)
+
+	scriptLesson(t, "")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.LessonState(user.ID, lesson.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("lesson state error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStartLessonInputEnds(t *testing.T) {
+	s := store.NewMemory()
+	user, lesson := seedLesson(t, s,
+		translation("Hallo", "Hello"),
+		translation("Danke", "Thank you"),
+		translation("Guten Morgen", "Good morning"),
+	)
+
+	// The answers run out after the first exercise
+	scriptLesson(t, "hello\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := s.User(user.ID)
+	if saved.XP != 0 || saved.Hearts != 5 {
+		t.Errorf("user has %d XP and %d hearts, want 0 and 5", saved.XP, saved.Hearts)
+	}
+	if rows, _ := s.ProgressSince(user.ID, time.Time{}); len(rows) != 0 {
+		t.Errorf("got %d progress rows, want none", len(rows))
+	}
+	if _, err := s.LessonState(user.ID, lesson.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("lesson state error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStartLessonPairsAskedOnce(t *testing.T) {
+	s := store.NewMemory()
+	for _, word := range []models.Vocabulary{
+		{Key: "der hund", German: "Hund", Gender: "der", English: "the dog"},
+		{Key: "die katze", German: "Katze", Gender: "die", English: "the cat"},
+	} {
+		if err := s.SaveWord(&word); err != nil {
+			t.Fatal(err)
+		}
+	}
+	user, lesson := seedLesson(t, s,
+		models.Exercise{Type: "match_pairs", Question: "Match", Options: `["der hund", "die katze"]`},
+	)
+
+	// Both words get meaning a, so exactly one pair is right whatever the
+	// shuffle. A retry would find no answer left and abandon the lesson.
+	scriptLesson(t, "1a 2a\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := s.User(user.ID)
+	if saved.XP != 3 || saved.Hearts != 4 {
+		t.Errorf("user has %d XP and %d hearts, want 3 and 4", saved.XP, saved.Hearts)
+	}
+	rows, _ := s.ProgressSince(user.ID, time.Time{})
+	if len(rows) != 1 || rows[0].Credit != 0.5 || rows[0].Attempts != 1 {
+		t.Errorf("got progress rows %+v, want one with half credit after 1 attempt", rows)
+	}
+}
+
+func TestStartLessonRetriesUntilCorrect(t *testing.T) {
+	s := store.NewMemory()
+	user, lesson := seedLesson(t, s, translation("Hallo", "Hello"))
+
+	scriptLesson(t, "goodbye\nthanks\nplease\nhello\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := s.User(user.ID)
+	if saved.Hearts != 2 {
+		t.Errorf("user has %d hearts, want 2", saved.Hearts)
+	}
+	rows, _ := s.ProgressSince(user.ID, time.Time{})
+	if len(rows) != 1 || !rows[0].IsCorrect || rows[0].Attempts != 4 {
+		t.Errorf("got progress rows %+v, want one correct after 4 attempts", rows)
+	}
+}
+
+func TestStartLessonOutOfHearts(t *testing.T) {
+	s := store.NewMemory()
+	user, lesson := seedLesson(t, s,
+		translation("Hallo", "Hello"),
+		translation("Danke", "Thank you"),
+	)
+	user.Hearts = 1
+	user.HeartsUpdatedAt = time.Now()
+	if err := s.SaveUser(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	scriptLesson(t, "goodbye\nthank you\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := s.User(user.ID)
+	if saved.Hearts != 0 {
+		t.Errorf("user has %d hearts, want 0", saved.Hearts)
+	}
+	if state, _ := s.LessonState(user.ID, lesson.ID); state.Status == models.LessonCompleted {
+		t.Error("lesson completed although hearts ran out")
+	}
+	if rows, _ := s.ProgressSince(user.ID, time.Time{}); len(rows) != 1 {
+		t.Errorf("got %d progress rows, want 1 before hearts ran out", len(rows))
+	}
+
+	// Without hearts the next lesson doesn't start
+	scriptLesson(t, "hello\nthank you\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := s.ProgressSince(user.ID, time.Time{}); len(rows) != 1 {
+		t.Errorf("got %d progress rows, want no new ones without hearts", len(rows))
+	}
+}
+
+func TestStartLessonHeartsOff(t *testing.T) {
+	s := store.NewMemory()
+	user, lesson := seedLesson(t, s, translation("Hallo", "Hello"))
+	if err := settings.Set(s, user.ID, settings.Hearts, "off"); err != nil {
+		t.Fatal(err)
+	}
+
+	scriptLesson(t, "goodbye\nhello\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := s.User(user.ID)
+	if saved.Hearts != 5 {
+		t.Errorf("user has %d hearts, want 5 with hearts off", saved.Hearts)
+	}
+}
+
+// failingStore fails every user save, including the one that ends a lesson
+// transaction after the progress rows have been written
+type failingStore struct {
+	*store.Memory
+}
+
+func (f failingStore) SaveUser(user *models.User) error {
+	return errors.New("disk full")
+}
+
+func (f failingStore) Transaction(fn func(tx store.Store) error) error {
+	return f.Memory.Transaction(func(store.Store) error { return fn(f) })
+}
+
+func TestStartLessonInterruptSave(t *testing.T) {
+	s := store.NewMemory()
+	user, lesson := seedLesson(t, s,
+		translation("Hallo", "Hello"),
+		translation("Danke", "Thank you"),
+		translation("Guten Morgen", "Good morning"),
+	)
+
+	// Ctrl-C during the second exercise, then save what was finished
+	scriptLesson(t, "hello\n"+console.InterruptLine+"\ns\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := s.User(user.ID)
+	if saved.XP != 5 {
+		t.Errorf("user has %d XP, want the 5 of the finished exercise", saved.XP)
+	}
+	rows, _ := s.ProgressSince(user.ID, time.Time{})
+	exercises, _ := s.LessonExercises(lesson.ID)
+	if len(rows) != 1 || rows[0].ExerciseID != exercises[0].ID {
+		t.Errorf("got progress rows %+v, want only the first exercise", rows)
+	}
+	if _, err := s.ReviewItem(user.ID, review.ItemExercise, exercises[0].ID); err != nil {
+		t.Errorf("finished exercise was not scheduled: %v", err)
+	}
+	// A partial save is not an attempt at the lesson
+	if _, err := s.LessonState(user.ID, lesson.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("lesson state error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStartLessonInterruptAbandon(t *testing.T) {
+	s := store.NewMemory()
+	user, lesson := seedLesson(t, s,
+		translation("Hallo", "Hello"),
+		translation("Danke", "Thank you"),
+	)
+
+	scriptLesson(t, "hello\n"+console.InterruptLine+"\na\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := s.User(user.ID)
+	if saved.XP != 0 {
+		t.Errorf("user has %d XP, want 0", saved.XP)
+	}
+	if rows, _ := s.ProgressSince(user.ID, time.Time{}); len(rows) != 0 {
+		t.Errorf("got %d progress rows, want none", len(rows))
+	}
+	if items, _ := s.DueReviewItems(user.ID, time.Now().AddDate(1, 0, 0), 0); len(items) != 0 {
+		t.Errorf("got %d review items, want none", len(items))
+	}
+	if _, err := s.LessonState(user.ID, lesson.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("lesson state error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStartLessonInterruptSaveFails(t *testing.T) {
+	s := store.NewMemory()
+	hallo := models.Vocabulary{German: "Hallo", English: "Hello"}
+	if err := s.SaveWord(&hallo); err != nil {
+		t.Fatal(err)
+	}
+	greeting := translation("Hallo", "Hello")
+	greeting.Vocabulary = []models.Vocabulary{hallo}
+	user, lesson := seedLesson(t, s, greeting, translation("Danke", "Thank you"))
+
+	scriptLesson(t, "hello\n"+console.InterruptLine+"\ns\n")
+	if err := StartLesson(failingStore{s}, user.ID, lesson.ID); err == nil {
+		t.Fatal("StartLesson succeeded although the user could not be saved")
+	}
+
+	// The progress, schedule and strength written before the failure are
+	// rolled back with it
+	if rows, _ := s.ProgressSince(user.ID, time.Time{}); len(rows) != 0 {
+		t.Errorf("got %d progress rows, want none", len(rows))
+	}
+	if items, _ := s.DueReviewItems(user.ID, time.Now().AddDate(1, 0, 0), 0); len(items) != 0 {
+		t.Errorf("got %d review items, want none", len(items))
+	}
+	if rows, _ := s.WordStrengths(user.ID); len(rows) != 0 {
+		t.Errorf("got %d strength rows, want none", len(rows))
+	}
+}
+
+func TestStartLessonStrengthOncePerWord(t *testing.T) {
+	s := store.NewMemory()
+	hallo := models.Vocabulary{German: "Hallo", English: "Hello"}
+	if err := s.SaveWord(&hallo); err != nil {
+		t.Fatal(err)
+	}
+	greeting := translation("Hallo", "Hello")
+	greeting.Vocabulary = []models.Vocabulary{hallo}
+	again := translation("Hallo!", "Hello!")
+	again.Vocabulary = []models.Vocabulary{hallo}
+	user, lesson := seedLesson(t, s, greeting, again)
+
+	// A miss, then both right, practises Hallo three times in one lesson
+	scriptLesson(t, "bye\nhello!\nhello\n")
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := s.WordStrength(user.ID, hallo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Answers != 1 || ws.Correct != 1 {
+		t.Errorf("Hallo strength: %d answers, %d correct; want one correct update", ws.Answers, ws.Correct)
+	}
+}
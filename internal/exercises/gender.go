@@ -0,0 +1,129 @@
+package exercises
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/grammar"
+	"duocli/internal/models"
+	"duocli/internal/progress"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"duocli/internal/ui"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// genderNoun is a vocabulary noun split into its article and bare noun
+type genderNoun struct {
+	word    models.Vocabulary
+	article string
+	noun    string
+}
+
+// StartGenderDrill quizzes the article of nouns from the vocabulary, asking
+// about the nouns the user gets wrong most often first
+func StartGenderDrill(s store.Store, userID uint, count int) error {
+	nouns := genderNouns(s)
+	if len(nouns) == 0 {
+		return fmt.Errorf("no nouns with der, die or das in the vocabulary")
+	}
+
+	// Weakest nouns first, unseen ones next, random within each group
+	stats := progress.NounGenders(s, userID)
+	rand.Shuffle(len(nouns), func(i, j int) {
+		nouns[i], nouns[j] = nouns[j], nouns[i]
+	})
+	sort.SliceStable(nouns, func(i, j int) bool {
+		return wordWeight(stats[nouns[i].word.ID]) < wordWeight(stats[nouns[j].word.ID])
+	})
+	if count > 0 && len(nouns) > count {
+		nouns = nouns[:count]
+	}
+
+	color.Cyan("\n🎯 Gender Drill")
+	color.Yellow("💪 %d nouns. Answer der, die or das (or 1, 2, 3).", len(nouns))
+
+	asked := 0
+	correct := 0
+	xpEarned := 0
+	words := sessionWords{}
+	for i, noun := range nouns {
+		color.Blue("\n🎯 %d/%d", i+1, len(nouns))
+		console.Printf("___ %s  (%s)\n", noun.noun, noun.word.English)
+		console.Printf("1. der  2. die  3. das\n> ")
+
+		given, ok := readGender()
+		if !ok {
+			break
+		}
+		asked++
+
+		if err := progress.RecordGender(s, userID, noun.word.ID, noun.article, given); err != nil {
+			return fmt.Errorf("failed to save answer: %w", err)
+		}
+
+		quality := review.QualityWrong
+		if given == noun.article {
+			correct++
+			xpEarned += drillXP()
+			quality = review.QualityCorrect
+			color.Green("✅ %s %s (+%d XP)", noun.article, noun.noun, drillXP())
+		} else {
+			color.Red("❌ It's %s %s", ui.ColorGender(noun.article), noun.noun)
+		}
+		if err := recordItem(s, userID, words, review.ItemVocabulary, noun.word.ID, quality); err != nil {
+			return fmt.Errorf("failed to save answer: %w", err)
+		}
+	}
+
+	if err := words.save(s, userID); err != nil {
+		return fmt.Errorf("failed to save word strengths: %w", err)
+	}
+
+	user, err := s.User(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if err := awardXP(s, &user, xpEarned); err != nil {
+		return err
+	}
+
+	console.Println("\n" + strings.Repeat("=", 50))
+	color.Cyan("🎯 GENDER DRILL COMPLETE!")
+	console.Println(strings.Repeat("=", 50))
+	if asked > 0 {
+		color.White("Correct: %d/%d (%.1f%%)", correct, asked, float64(correct)/float64(asked)*100)
+	}
+	color.Green("XP Earned: +%d", xpEarned)
+
+	return nil
+}
+
+// readGender reads an article, asking again until the answer is one of
+// der, die or das. ok is false when input runs out.
+func readGender() (string, bool) {
+	for {
+		answer, ok := console.ReadLine()
+		if !ok {
+			return "", false
+		}
+		if gender, ok := grammar.ParseGender(answer); ok {
+			return gender, true
+		}
+		console.Print("Please answer der, die or das: ")
+	}
+}
+
+// genderNouns returns every vocabulary noun with a known gender
+func genderNouns(s store.VocabularyStore) []genderNoun {
+	vocab, _ := s.Words(store.WordQuery{Nouns: true})
+
+	nouns := make([]genderNoun, len(vocab))
+	for i, word := range vocab {
+		nouns[i] = genderNoun{word: word, article: word.Gender, noun: word.German}
+	}
+	return nouns
+}
@@ -0,0 +1,68 @@
+package exercises
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/progress"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"testing"
+)
+
+func TestStartGenderDrill(t *testing.T) {
+	s := store.NewMemory()
+	user := models.User{Name: "tester"}
+	if err := s.CreateUser(&user); err != nil {
+		t.Fatal(err)
+	}
+	words := []models.Vocabulary{
+		{German: "Haus", English: "house", Gender: "das", PartOfSpeech: "noun"},
+		{German: "Katze", English: "cat", Gender: "die", PartOfSpeech: "noun"},
+		{German: "Hund", English: "dog", Gender: "der", PartOfSpeech: "noun"},
+	}
+	for i := range words {
+		if err := s.SaveWord(&words[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	haus, katze, hund := words[0], words[1], words[2]
+
+	// Hund was missed, Katze is unseen and Haus was always right
+	if err := progress.RecordGender(s, user.ID, hund.ID, "der", "die"); err != nil {
+		t.Fatal(err)
+	}
+	if err := progress.RecordGender(s, user.ID, haus.ID, "das", "das"); err != nil {
+		t.Fatal(err)
+	}
+
+	// An answer that isn't an article is asked again
+	scriptLesson(t, "der\nhund\n1\n")
+	if err := StartGenderDrill(s, user.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := progress.NounGenders(s, user.ID)
+	tests := []struct {
+		word             models.Vocabulary
+		answers, correct int
+	}{
+		{hund, 2, 1},
+		{katze, 1, 0},
+		{haus, 1, 1},
+	}
+	for _, tt := range tests {
+		if got := stats[tt.word.ID]; got.Answers != tt.answers || got.Correct != tt.correct {
+			t.Errorf("%s: %d answers, %d correct; want %d and %d", tt.word.German, got.Answers, got.Correct, tt.answers, tt.correct)
+		}
+	}
+
+	missed, err := s.ReviewItem(user.ID, review.ItemVocabulary, katze.ID)
+	if err != nil || missed.Lapses != 1 {
+		t.Errorf("Katze review item %+v (%v), want one lapse", missed, err)
+	}
+	if _, err := s.ReviewItem(user.ID, review.ItemVocabulary, haus.ID); err == nil {
+		t.Error("Haus was scheduled although it wasn't asked")
+	}
+	if saved, _ := s.User(user.ID); saved.XP != 2 {
+		t.Errorf("user has %d XP, want 2", saved.XP)
+	}
+}
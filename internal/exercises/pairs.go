@@ -0,0 +1,219 @@
+package exercises
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/grading"
+	"duocli/internal/models"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// pairCount is how many words a matching exercise shows
+const pairCount = 5
+
+// pairLetters label the shuffled meanings of a matching exercise
+const pairLetters = "abcdefghij"
+
+// exerciseVocabulary returns the words a generated exercise draws from, in
+// random order: the vocabulary keys listed in its options, or else every
+// word in category, or every word when category is empty
+func exerciseVocabulary(s store.VocabularyStore, exercise models.Exercise, category string) []models.Vocabulary {
+	var keys []string
+	if exercise.Options != "" {
+		json.Unmarshal([]byte(exercise.Options), &keys)
+	}
+
+	query := store.WordQuery{Keys: keys}
+	if len(keys) == 0 {
+		query.Category = category
+	}
+	words, _ := s.Words(query)
+
+	rand.Shuffle(len(words), func(i, j int) {
+		words[i], words[j] = words[j], words[i]
+	})
+	return words
+}
+
+// matchVocabulary returns the words for a match_pairs exercise, taken from
+// its options or from the category named by its parameter
+func matchVocabulary(s store.VocabularyStore, exercise models.Exercise) []models.Vocabulary {
+	words := exerciseVocabulary(s, exercise, exercise.Parameter)
+	if len(words) > pairCount {
+		words = words[:pairCount]
+	}
+	return words
+}
+
+// CategoryMatch builds a match_pairs exercise over a vocabulary category
+func CategoryMatch(category string) models.Exercise {
+	return models.Exercise{
+		Type:      "match_pairs",
+		Question:  fmt.Sprintf("Match these %s words with their meanings", category),
+		Parameter: category,
+	}
+}
+
+func handleMatchPairs(s store.VocabularyStore, exercise models.Exercise) (grading.Result, bool) {
+	words := matchVocabulary(s, exercise)
+	if len(words) < 2 {
+		color.Yellow("⚠️  Not enough vocabulary to match, skipping.")
+		return grading.Result{Verdict: grading.Skipped}, true
+	}
+
+	meanings := make([]int, len(words))
+	for i := range meanings {
+		meanings[i] = i
+	}
+	rand.Shuffle(len(meanings), func(i, j int) {
+		meanings[i], meanings[j] = meanings[j], meanings[i]
+	})
+
+	console.Println()
+	for i, word := range words {
+		console.Printf("  %d. %-20s %c. %s\n", i+1, word.WithArticle(), pairLetters[i], words[meanings[i]].English)
+	}
+
+	console.Print("Your matches (e.g. 1c 2a 3e): ")
+	answer, ok := console.ReadLine()
+	if !ok {
+		return grading.Result{}, false
+	}
+
+	english := make([]string, len(meanings))
+	for i, m := range meanings {
+		english[i] = words[m].English
+	}
+	picked := parsePairs(answer, english)
+
+	expected := make([]string, len(words))
+	for i := range words {
+		for letter, m := range meanings {
+			if m == i {
+				expected[i] = fmt.Sprintf("%d%c", i+1, pairLetters[letter])
+			}
+		}
+	}
+
+	result := grading.Result{Given: answer, Expected: strings.Join(expected, " "), Pairs: map[uint]bool{}}
+	correct := 0
+	for i, word := range words {
+		letter, ok := picked[i]
+		mark := "❌"
+		result.Pairs[word.ID] = ok && meanings[letter] == i
+		if result.Pairs[word.ID] {
+			correct++
+			mark = "✅"
+		}
+		color.White("  %s %s = %s", mark, word.WithArticle(), word.English)
+	}
+
+	result.Credit = float64(correct) / float64(len(words))
+	result.Note = fmt.Sprintf("%d/%d pairs matched", correct, len(words))
+	if correct == len(words) {
+		result.Verdict = grading.Correct
+	}
+	return result, true
+}
+
+// parsePairs reads matches such as "1c 2a", "1-c, 2-a" or "1 dog, 2 cat"
+// into word index → meaning index. A bare list of letters ("c a e b d")
+// matches the words in order.
+func parsePairs(answer string, meanings []string) map[int]int {
+	picked := map[int]int{}
+
+	sep := func(r rune) bool { return r == ' ' || r == '\t' }
+	if strings.Contains(answer, ",") {
+		sep = func(r rune) bool { return r == ',' }
+	}
+
+	for i, field := range strings.FieldsFunc(strings.ToLower(answer), sep) {
+		field = strings.TrimSpace(field)
+
+		// Leading digits name the word; without them the position does
+		digits := 0
+		for digits < len(field) && field[digits] >= '0' && field[digits] <= '9' {
+			digits++
+		}
+		word := i
+		if digits > 0 {
+			word, _ = strconv.Atoi(field[:digits])
+			word--
+		}
+		rest := strings.TrimSpace(strings.TrimLeft(field[digits:], " -=:."))
+
+		meaning := -1
+		if len(rest) == 1 && strings.IndexByte(pairLetters, rest[0]) >= 0 {
+			meaning = strings.IndexByte(pairLetters, rest[0])
+		} else {
+			for m, text := range meanings {
+				if grading.Grade(rest, text).Verdict == grading.Correct {
+					meaning = m
+				}
+			}
+		}
+
+		if word >= 0 && word < len(meanings) && meaning >= 0 && meaning < len(meanings) {
+			picked[word] = meaning
+		}
+	}
+
+	return picked
+}
+
+// StartMatch runs a standalone matching round over a vocabulary category
+func StartMatch(s store.Store, userID uint, category string) error {
+	words, err := s.Words(store.WordQuery{Category: category})
+	if err != nil {
+		return err
+	}
+	if len(words) < 2 {
+		return fmt.Errorf("not enough vocabulary in category %q", category)
+	}
+
+	exercise := CategoryMatch(category)
+	color.Cyan("\n🧩 Matching Pairs: %s", category)
+
+	result, answered := runExercise(s, exercise)
+	if !answered {
+		return nil
+	}
+	xp := showVerdict(result, exercise)
+	emitAnswer(exercise, result, 1, xp)
+	if err := recordPairs(s, userID, result); err != nil {
+		return fmt.Errorf("failed to save answers: %w", err)
+	}
+
+	user, err := s.User(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	return awardXP(s, &user, xp)
+}
+
+// recordPairs feeds each matched word into the review scheduler and the
+// learner's word strengths
+func recordPairs(s store.Store, userID uint, result grading.Result) error {
+	words := sessionWords{}
+	for wordID, correct := range result.Pairs {
+		if err := recordItem(s, userID, words, review.ItemVocabulary, wordID, pairQuality(correct)); err != nil {
+			return err
+		}
+	}
+	return words.save(s, userID)
+}
+
+// pairQuality is the review quality of a matched or mismatched pair
+func pairQuality(correct bool) int {
+	if correct {
+		return review.QualityCorrect
+	}
+	return review.QualityWrong
+}
@@ -0,0 +1,32 @@
+package exercises
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePairs(t *testing.T) {
+	meanings := []string{"dog", "cat", "house"}
+
+	tests := []struct {
+		answer string
+		want   map[int]int
+	}{
+		{"1c 2a 3b", map[int]int{0: 2, 1: 0, 2: 1}},
+		{"1-c, 2-a, 3-b", map[int]int{0: 2, 1: 0, 2: 1}},
+		{"1 = C, 2: a", map[int]int{0: 2, 1: 0}},
+		{"c a b", map[int]int{0: 2, 1: 0, 2: 1}},
+		{"1 house, 2 dog, 3 cat", map[int]int{0: 2, 1: 0, 2: 1}},
+		{"1 hous, 2 dgo", map[int]int{}},
+		{"house, dog", map[int]int{0: 2, 1: 0}},
+		{"3a 2a", map[int]int{2: 0, 1: 0}},
+		{"4a 1z 0b", map[int]int{}},
+		{"", map[int]int{}},
+	}
+
+	for _, tt := range tests {
+		if got := parsePairs(tt.answer, meanings); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePairs(%q) = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package exercises
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/grammar"
+	"duocli/internal/models"
+	"duocli/internal/progress"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+// Vocabulary practice modes
+const (
+	PracticeMixed = "mixed"
+	GermanEnglish = "de-en"
+	EnglishGerman = "en-de"
+	PracticeCloze = "cloze"
+)
+
+// PracticeModes lists the modes accepted by StartVocabPractice
+var PracticeModes = []string{PracticeMixed, GermanEnglish, EnglishGerman, PracticeCloze}
+
+// StartVocabPractice quizzes words from a vocabulary category, or from all
+// words when category is empty, in the given mode. Words with the worst
+// practice record come first.
+func StartVocabPractice(s store.Store, userID uint, category string, count int, mode string) error {
+	words, err := s.Words(store.WordQuery{Category: category})
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("no vocabulary in category %q", category)
+	}
+
+	results := progress.WordResults(s, userID)
+	rand.Shuffle(len(words), func(i, j int) {
+		words[i], words[j] = words[j], words[i]
+	})
+	sort.SliceStable(words, func(i, j int) bool {
+		return wordWeight(results[words[i].ID]) < wordWeight(results[words[j].ID])
+	})
+	if count > 0 && len(words) > count {
+		words = words[:count]
+	}
+
+	title := category
+	if title == "" {
+		title = "all words"
+	}
+	color.Cyan("\n📖 Vocabulary Practice: %s", title)
+	color.Yellow("💪 %d words\n", len(words))
+
+	emit(TranscriptEvent{Event: "practice_start", Total: len(words)})
+
+	correct := 0
+	xpEarned := 0
+	var missed []string
+	practised := sessionWords{}
+	for i, word := range words {
+		exerciseMode, exercise := practiceExercise(word, mode)
+
+		color.Blue("\n📖 %d/%d", i+1, len(words))
+
+		result, answered := runExercise(s, exercise)
+		if !answered {
+			break // Input ran out; the words left are not graded
+		}
+		xp := showVerdict(result, exercise)
+		xpEarned += xp
+		emitAnswer(exercise, result, 1, xp)
+
+		accepted := result.Verdict.Accepted()
+		if err := progress.RecordVocab(s, userID, word.ID, exerciseMode, accepted); err != nil {
+			return fmt.Errorf("failed to save answer: %w", err)
+		}
+		if accepted {
+			correct++
+		} else {
+			missed = append(missed, fmt.Sprintf("%s = %s", word.WithArticle(), word.English))
+		}
+		if err := recordItem(s, userID, practised, review.ItemVocabulary, word.ID, reviewQuality(result.Verdict)); err != nil {
+			return fmt.Errorf("failed to save answer: %w", err)
+		}
+
+		pause()
+	}
+
+	if err := practised.save(s, userID); err != nil {
+		return fmt.Errorf("failed to save word strengths: %w", err)
+	}
+
+	user, err := s.User(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if err := awardXP(s, &user, xpEarned); err != nil {
+		return err
+	}
+
+	emit(TranscriptEvent{Event: "practice_end", Score: correct, Total: len(words), XP: xpEarned})
+
+	console.Println("\n" + strings.Repeat("=", 50))
+	color.Cyan("📖 PRACTICE COMPLETE!")
+	console.Println(strings.Repeat("=", 50))
+	color.White("Score: %d/%d", correct, len(words))
+	color.Green("XP Earned: +%d", xpEarned)
+	if len(missed) > 0 {
+		color.Yellow("Words to look at again:")
+		for _, word := range missed {
+			color.White("  • %s", word)
+		}
+	}
+	console.Println(strings.Repeat("=", 50))
+
+	return nil
+}
+
+// practiceExercise builds a prompt for a word. Mixed mode picks a direction
+// at random and uses a cloze prompt when the word has an example sentence;
+// cloze mode falls back to a translation prompt when it has none.
+func practiceExercise(word models.Vocabulary, mode string) (string, models.Exercise) {
+	if mode == PracticeMixed {
+		mode = []string{GermanEnglish, EnglishGerman, PracticeCloze}[rand.Intn(3)]
+	}
+
+	if mode == PracticeCloze {
+		if exercise, ok := clozeExercise(word); ok {
+			return PracticeCloze, exercise
+		}
+		mode = EnglishGerman
+	}
+
+	if mode == GermanEnglish {
+		// Every meaning counts, with or without "the" or "to"
+		alternatives := strings.Split(word.English, "/")
+		alternatives = append(alternatives, meanings(word.English)...)
+		data, _ := json.Marshal(alternatives)
+		return GermanEnglish, models.Exercise{
+			Type:         "translation",
+			Question:     fmt.Sprintf("What does '%s' mean in English?", word.WithArticle()),
+			Answer:       word.English,
+			Alternatives: string(data),
+		}
+	}
+
+	return EnglishGerman, models.Exercise{
+		Type:     "translation",
+		Question: fmt.Sprintf("How do you say '%s' in German?", word.English),
+		Answer:   word.WithArticle(),
+	}
+}
+
+// clozeExercise blanks the word out of its example sentence. ok is false
+// when the word has no example or doesn't appear in it.
+func clozeExercise(word models.Vocabulary) (models.Exercise, bool) {
+	if word.Example == "" {
+		return models.Exercise{}, false
+	}
+
+	forms := wordForms(word)
+	fields := strings.Fields(word.Example)
+	for i, field := range fields {
+		core := strings.TrimFunc(field, unicode.IsPunct)
+		if core == "" || !containsFold(forms, core) {
+			continue
+		}
+
+		fields[i] = strings.Replace(field, core, "___", 1)
+		question := strings.Join(fields, " ")
+		if word.Translation != "" {
+			question += fmt.Sprintf("\n(%s)", word.Translation)
+		}
+		return models.Exercise{
+			Type:     "fill_blank",
+			Question: question,
+			Answer:   core,
+		}, true
+	}
+
+	return models.Exercise{}, false
+}
+
+// wordForms returns the forms a word can take in a sentence: the plural of
+// nouns and every conjugated form of verbs
+func wordForms(word models.Vocabulary) []string {
+	forms := []string{word.German}
+	if word.Plural != "" {
+		forms = append(forms, word.Plural)
+	}
+	if word.PartOfSpeech != "verb" {
+		return forms
+	}
+
+	conjugation, err := grammar.ConjugateSeparable(word.German, word.SeparablePrefix)
+	if err != nil {
+		return forms
+	}
+	for _, tense := range []grammar.Tense{grammar.Present, grammar.Preterite} {
+		for _, form := range conjugation.Forms[tense] {
+			if fields := strings.Fields(form); len(fields) > 0 {
+				forms = append(forms, fields[0])
+			}
+		}
+	}
+	return append(forms, conjugation.Participle)
+}
@@ -0,0 +1,71 @@
+package exercises
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/progress"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"testing"
+)
+
+func TestStartVocabPractice(t *testing.T) {
+	s := store.NewMemory()
+	user := models.User{Name: "tester"}
+	if err := s.CreateUser(&user); err != nil {
+		t.Fatal(err)
+	}
+	words := []models.Vocabulary{
+		{German: "Pferd", English: "horse", Gender: "das", Category: "animals"},
+		{German: "Elefant", English: "elephant", Gender: "der", Category: "animals"},
+		{German: "Hund", English: "dog", Gender: "der", Category: "animals"},
+	}
+	for i := range words {
+		if err := s.SaveWord(&words[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	horse, elephant, dog := words[0], words[1], words[2]
+
+	// The missed dog comes first, then the unseen elephant; the horse was
+	// always right and is left out
+	for _, answer := range []struct {
+		word    models.Vocabulary
+		correct bool
+	}{{dog, false}, {dog, true}, {horse, true}, {horse, true}} {
+		if err := progress.RecordVocab(s, user.ID, answer.word.ID, GermanEnglish, answer.correct); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scriptLesson(t, "dog\nelephnt\n")
+	if err := StartVocabPractice(s, user.ID, "animals", 2, GermanEnglish); err != nil {
+		t.Fatal(err)
+	}
+
+	results := progress.WordResults(s, user.ID)
+	tests := []struct {
+		word             models.Vocabulary
+		answers, correct int
+	}{
+		{dog, 3, 2},
+		{elephant, 1, 1},
+		{horse, 2, 2},
+	}
+	for _, tt := range tests {
+		if got := results[tt.word.ID]; got.Answers != tt.answers || got.Correct != tt.correct {
+			t.Errorf("%s: %d answers, %d correct; want %d and %d", tt.word.German, got.Answers, got.Correct, tt.answers, tt.correct)
+		}
+	}
+
+	// A typo is scheduled as a hard answer, not a correct one
+	typo, err := s.ReviewItem(user.ID, review.ItemVocabulary, elephant.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typo.Repetitions != 1 || typo.EaseFactor >= 2.5 {
+		t.Errorf("typo scheduled with %d repetitions and ease %.2f, want 1 and below 2.5", typo.Repetitions, typo.EaseFactor)
+	}
+	if saved, _ := s.User(user.ID); saved.XP != 5+3 {
+		t.Errorf("user has %d XP, want 8", saved.XP)
+	}
+}
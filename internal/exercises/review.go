@@ -0,0 +1,133 @@
+package exercises
+
+import (
+	"duocli/internal/console"
+	"duocli/internal/grading"
+	"duocli/internal/hearts"
+	"duocli/internal/models"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// StartReview runs a spaced-repetition session over the user's due items
+func StartReview(s store.Store, userID uint, limit int) error {
+	items, err := review.Due(s, userID, limit)
+	if err != nil {
+		return fmt.Errorf("failed to load review queue: %w", err)
+	}
+
+	if len(items) == 0 {
+		color.Green("🎉 Nothing to review right now. Come back later!")
+		return nil
+	}
+
+	loadChoicePrefs(s, userID)
+
+	color.Cyan("\n🔁 Review Session")
+	color.Yellow("💪 %d items due for review\n", len(items))
+
+	emit(TranscriptEvent{Event: "review_start", Total: len(items)})
+
+	score := 0
+	xpEarned := 0
+	words := sessionWords{}
+	for i, item := range items {
+		exercise, ok := reviewExercise(s, item)
+		if !ok {
+			continue
+		}
+
+		color.Blue("\n🔁 Review %d/%d", i+1, len(items))
+
+		result, answered := runExercise(s, exercise)
+		if !answered {
+			break // Input ran out; the rest stays due
+		}
+		if result.Verdict == grading.Skipped {
+			continue // Stays due until it can be asked
+		}
+		if result.Verdict.Accepted() {
+			score++
+		}
+		xp := showVerdict(result, exercise)
+		xpEarned += xp
+		emitAnswer(exercise, result, 1, xp)
+
+		review.Schedule(&item, reviewQuality(result.Verdict), time.Now())
+		if err := s.SaveReviewItem(&item); err != nil {
+			return fmt.Errorf("failed to save answer: %w", err)
+		}
+		if err := words.add(s, item.ItemType, item.ItemID, result.Verdict.Accepted()); err != nil {
+			return fmt.Errorf("failed to save answer: %w", err)
+		}
+
+		pause()
+	}
+
+	if err := words.save(s, userID); err != nil {
+		return fmt.Errorf("failed to save word strengths: %w", err)
+	}
+
+	user, err := s.User(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	// Each correct review answer earns a heart back
+	if hearts.Enabled(s, userID) && score > 0 {
+		hearts.Refresh(&user, time.Now())
+		before := user.Hearts
+		if err := hearts.Gain(s, &user, score); err != nil {
+			return fmt.Errorf("failed to save hearts: %w", err)
+		}
+		if user.Hearts > before {
+			color.Red("❤️  +%d hearts: %s", user.Hearts-before, hearts.Display(user.Hearts))
+		}
+	}
+
+	if err := awardXP(s, &user, xpEarned); err != nil {
+		return err
+	}
+
+	emit(TranscriptEvent{Event: "review_end", Score: score, Total: len(items), XP: xpEarned})
+
+	console.Println("\n" + strings.Repeat("=", 50))
+	color.Cyan("🔁 REVIEW COMPLETE!")
+	console.Println(strings.Repeat("=", 50))
+	color.White("Score: %d/%d", score, len(items))
+	color.Green("XP Earned: +%d", xpEarned)
+	color.Yellow("Items still due: %d", review.DueCount(s, userID))
+	console.Println(strings.Repeat("=", 50))
+
+	return nil
+}
+
+// reviewExercise builds the exercise to show for a review item. Vocabulary
+// items are turned into an English to German translation prompt.
+func reviewExercise(s store.Store, item models.ReviewItem) (models.Exercise, bool) {
+	switch item.ItemType {
+	case review.ItemExercise:
+		exercise, err := s.Exercise(item.ItemID)
+		if err != nil {
+			return exercise, false
+		}
+		return exercise, true
+	case review.ItemVocabulary:
+		vocab, err := s.Word(item.ItemID)
+		if err != nil {
+			return models.Exercise{}, false
+		}
+		return models.Exercise{
+			Type:     "translation",
+			Question: fmt.Sprintf("How do you say '%s' in German?", vocab.English),
+			Answer:   vocab.WithArticle(),
+		}, true
+	}
+
+	return models.Exercise{}, false
+}
@@ -0,0 +1,62 @@
+package exercises
+
+import (
+	"duocli/internal/grading"
+	"duocli/internal/models"
+	"encoding/json"
+	"io"
+)
+
+// TranscriptEvent is one line of a machine-readable session transcript
+type TranscriptEvent struct {
+	Event      string  `json:"event"` // lesson_start, answer, lesson_end, lesson_saved, lesson_abandoned, review_start, review_end
+	LessonID   uint    `json:"lesson_id,omitempty"`
+	ExerciseID uint    `json:"exercise_id,omitempty"`
+	Type       string  `json:"type,omitempty"`
+	Question   string  `json:"question,omitempty"`
+	Answer     string  `json:"answer,omitempty"`
+	Expected   string  `json:"expected,omitempty"`
+	Verdict    string  `json:"verdict,omitempty"`
+	Credit     float64 `json:"credit,omitempty"`
+	Attempt    int     `json:"attempt,omitempty"`
+	XP         int     `json:"xp,omitempty"`
+	Score      int     `json:"score,omitempty"`
+	FirstTry   int     `json:"first_try,omitempty"`
+	Total      int     `json:"total,omitempty"`
+	Percentage float64 `json:"percentage,omitempty"`
+	Passed     bool    `json:"passed,omitempty"`
+}
+
+var transcript *json.Encoder
+
+// SetTranscript writes a JSON line per session event to w. Pass nil to
+// turn the transcript off.
+func SetTranscript(w io.Writer) {
+	if w == nil {
+		transcript = nil
+		return
+	}
+	transcript = json.NewEncoder(w)
+}
+
+func emit(event TranscriptEvent) {
+	if transcript != nil {
+		transcript.Encode(event)
+	}
+}
+
+func emitAnswer(exercise models.Exercise, result grading.Result, attempt, xp int) {
+	emit(TranscriptEvent{
+		Event:      "answer",
+		LessonID:   exercise.LessonID,
+		ExerciseID: exercise.ID,
+		Type:       exercise.Type,
+		Question:   exercise.Question,
+		Answer:     result.Given,
+		Expected:   result.Expected,
+		Verdict:    result.Verdict.String(),
+		Credit:     credit(result),
+		Attempt:    attempt,
+		XP:         xp,
+	})
+}
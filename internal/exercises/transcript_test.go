@@ -0,0 +1,114 @@
+package exercises
+
+import (
+	"bytes"
+	"duocli/internal/config"
+	"duocli/internal/console"
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"encoding/json"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// chooser answers multiple choice prompts by reading the options printed so
+// far, as a learner would
+type chooser struct {
+	out     bytes.Buffer
+	answers []func(label string) string // Turn the label of the right option into an answer
+	right   string
+}
+
+func (c *chooser) Read(p []byte) (int, error) {
+	if len(c.answers) == 0 {
+		return 0, io.EOF
+	}
+	matches := regexp.MustCompile(`(?m)^(\w+)\. `+regexp.QuoteMeta(c.right)+`$`).FindAllStringSubmatch(c.out.String(), -1)
+	label := ""
+	if len(matches) > 0 {
+		label = matches[len(matches)-1][1]
+	}
+	answer := c.answers[0](label)
+	c.answers = c.answers[1:]
+	return copy(p, answer+"\n"), nil
+}
+
+func TestTranscript(t *testing.T) {
+	s := store.NewMemory()
+	choice := models.Exercise{Type: "multiple_choice", Question: "der Hund", Answer: "the dog", Options: `["the dog", "the cat", "the house"]`}
+	user, lesson := seedLesson(t, s, choice, choice, choice)
+
+	// The same question answered with the option's text, number and letter
+	c := &chooser{right: "the dog", answers: []func(string) string{
+		func(string) string { return "The Dog" },
+		func(label string) string { return label },
+		func(label string) string {
+			n, _ := strconv.Atoi(label)
+			return string(rune('a' + n - 1))
+		},
+	}}
+	conf := config.Default()
+	conf.Pause = 0
+	Configure(conf)
+	console.Use(console.New(c, &c.out))
+	var transcriptOut bytes.Buffer
+	SetTranscript(&transcriptOut)
+	t.Cleanup(func() {
+		SetTranscript(nil)
+		Configure(config.Default())
+	})
+
+	if err := StartLesson(s, user.ID, lesson.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []map[string]interface{}
+	decoder := json.NewDecoder(&transcriptOut)
+	for decoder.More() {
+		var event map[string]interface{}
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 5 {
+		t.Fatalf("got %d events, want lesson_start, 3 answers and lesson_end: %v", len(events), events)
+	}
+
+	keys := func(event map[string]interface{}) []string {
+		var names []string
+		for name := range event {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	wantKeys := map[string][]string{
+		"lesson_start": {"event", "lesson_id", "total"},
+		"answer":       {"answer", "attempt", "credit", "event", "exercise_id", "expected", "lesson_id", "question", "type", "verdict", "xp"},
+		"lesson_end":   {"event", "first_try", "lesson_id", "passed", "percentage", "score", "total", "xp"},
+	}
+	for i, event := range events {
+		name, _ := event["event"].(string)
+		if want := wantKeys[name]; !reflect.DeepEqual(keys(event), want) {
+			t.Errorf("event %d %q has fields %v, want %v", i, name, keys(event), want)
+		}
+	}
+	if events[0]["event"] != "lesson_start" || events[4]["event"] != "lesson_end" {
+		t.Errorf("transcript runs %v to %v, want lesson_start to lesson_end", events[0]["event"], events[4]["event"])
+	}
+
+	// Every form of the answer is recorded as the option it picked
+	for _, event := range events[1:4] {
+		if event["answer"] != "the dog" || event["verdict"] != "correct" || event["type"] != "multiple_choice" {
+			t.Errorf("answer event %v, want the dog graded correct", event)
+		}
+	}
+	if events[4]["score"] != 3.0 || events[4]["passed"] != true {
+		t.Errorf("lesson_end %v, want a score of 3 and passed", events[4])
+	}
+}
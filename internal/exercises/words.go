@@ -0,0 +1,76 @@
+package exercises
+
+import (
+	"duocli/internal/progress"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"sort"
+	"time"
+)
+
+// recordItem feeds an answer into the review scheduler and notes it for the
+// session's word strengths
+func recordItem(s store.Store, userID uint, words sessionWords, itemType string, itemID uint, quality int) error {
+	if err := review.Record(s, userID, itemType, itemID, quality); err != nil {
+		return err
+	}
+	return words.add(s, itemType, itemID, quality >= review.QualityHard)
+}
+
+// sessionWords collects the words a session practised and whether every
+// answer on them was right, so that a word's strength changes once per
+// session however many answers touched it
+type sessionWords map[uint]bool
+
+// add notes an answer on a vocabulary item, or on every word an exercise
+// practises
+func (w sessionWords) add(s store.Store, itemType string, itemID uint, correct bool) error {
+	ids := []uint{itemID}
+	if itemType == review.ItemExercise {
+		exercise, err := s.Exercise(itemID)
+		if err != nil {
+			return err
+		}
+		if ids, err = review.PractisedWords(s, exercise); err != nil {
+			return err
+		}
+	}
+	for _, wordID := range ids {
+		w.note(wordID, correct)
+	}
+	return nil
+}
+
+// note records an answer on a single word; one miss makes it a miss
+func (w sessionWords) note(wordID uint, correct bool) {
+	if seen, ok := w[wordID]; ok {
+		correct = correct && seen
+	}
+	w[wordID] = correct
+}
+
+// save updates the strength of each word once, in ID order
+func (w sessionWords) save(s store.Store, userID uint) error {
+	ids := make([]uint, 0, len(w))
+	for wordID := range w {
+		ids = append(ids, wordID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	now := time.Now()
+	for _, wordID := range ids {
+		if err := progress.RecordStrength(s, userID, wordID, w[wordID], now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wordWeight orders words for drilling and practice: missed ones by
+// accuracy, then unseen ones, then words that were always right
+func wordWeight(result progress.WordResult) float64 {
+	if result.Answers == 0 {
+		return 99.9
+	}
+	return result.Accuracy()
+}
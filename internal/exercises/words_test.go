@@ -0,0 +1,62 @@
+package exercises
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/review"
+	"duocli/internal/store"
+	"testing"
+)
+
+func TestSessionWords(t *testing.T) {
+	s := store.NewMemory()
+	hund := models.Vocabulary{German: "Hund", English: "dog"}
+	katze := models.Vocabulary{German: "Katze", English: "cat"}
+	for _, word := range []*models.Vocabulary{&hund, &katze} {
+		if err := s.SaveWord(word); err != nil {
+			t.Fatal(err)
+		}
+	}
+	linked := models.Exercise{Type: "translation", Question: "dog and cat", Answer: "Hund und Katze", Vocabulary: []models.Vocabulary{hund, katze}}
+	unlinked := models.Exercise{Type: "translation", Question: "dog", Answer: "Hund"}
+	for _, exercise := range []*models.Exercise{&linked, &unlinked} {
+		if err := s.SaveExercise(exercise); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Hund comes up three times, once missed; the exercise without linked
+	// words still finds it by its answer
+	words := sessionWords{}
+	for _, answer := range []struct {
+		itemType string
+		itemID   uint
+		correct  bool
+	}{
+		{review.ItemExercise, linked.ID, true},
+		{review.ItemVocabulary, hund.ID, false},
+		{review.ItemExercise, unlinked.ID, true},
+	} {
+		if err := words.add(s, answer.itemType, answer.itemID, answer.correct); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(words) != 2 || words[hund.ID] || !words[katze.ID] {
+		t.Fatalf("session words = %v, want Hund missed and Katze right", words)
+	}
+
+	if err := words.save(s, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []models.Vocabulary{hund, katze} {
+		ws, err := s.WordStrength(1, word.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ws.Answers != 1 {
+			t.Errorf("%s: strength changed %d times, want once", word.German, ws.Answers)
+		}
+	}
+	if ws, _ := s.WordStrength(1, hund.ID); ws.Correct != 0 {
+		t.Errorf("Hund counted %d correct answers, want the miss to count", ws.Correct)
+	}
+}
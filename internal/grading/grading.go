@@ -0,0 +1,212 @@
+package grading
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Verdict classifies a learner's answer
+type Verdict int
+
+const (
+	Wrong Verdict = iota
+	Correct
+	Typo
+	WrongArticle
+	Skipped // The exercise could not be asked; it counts neither way
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Correct:
+		return "correct"
+	case Typo:
+		return "typo"
+	case WrongArticle:
+		return "wrong_article"
+	case Skipped:
+		return "skipped"
+	default:
+		return "wrong"
+	}
+}
+
+// Accepted reports whether the answer counts as right. Typos are forgiven,
+// a wrong or missing article is not.
+func (v Verdict) Accepted() bool {
+	return v == Correct || v == Typo
+}
+
+// Result is the outcome of grading a single answer
+type Result struct {
+	Verdict   Verdict
+	Given     string
+	Expected  string
+	Note      string        // Extra feedback for typos, article and word order mistakes
+	Misplaced int           // Word order only: 1-based position of the first wrong word
+	Credit    float64       // Share of the answer that was right, for partial credit
+	Pairs     map[uint]bool // Matching only: whether each vocabulary ID was matched
+}
+
+var articles = map[string]bool{
+	"der": true, "die": true, "das": true, "den": true, "dem": true, "des": true,
+	"ein": true, "eine": true, "einen": true, "einem": true, "einer": true, "eines": true,
+}
+
+var transliterations = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+
+var umlautFolds = strings.NewReplacer("ä", "a", "ö", "o", "ü", "u", "ß", "ss")
+
+// Grade compares a typed answer against the expected one
+func Grade(given, expected string) Result {
+	result := Result{Given: given, Expected: expected}
+
+	g := Normalize(given)
+	e := Normalize(expected)
+
+	if g == "" {
+		return result
+	}
+
+	if g == e {
+		result.Verdict = Correct
+		return result
+	}
+
+	// Umlauts dropped entirely, e.g. "Tschuss" for "Tschüss"
+	if foldUmlauts(given) == foldUmlauts(expected) {
+		result.Verdict = Typo
+		result.Note = "Almost correct – watch the umlauts!"
+		return result
+	}
+
+	// Article checks only apply when the expected answer has one
+	expArticle, expNoun := splitArticle(e)
+	if expArticle != "" {
+		givenArticle, givenNoun := splitArticle(g)
+		if givenArticle != expArticle && nounMatches(givenNoun, expNoun) {
+			result.Verdict = WrongArticle
+			if givenArticle == "" {
+				result.Note = "Don't forget the article: " + strings.Fields(expected)[0]
+			} else {
+				result.Note = "Right word, wrong article – it's " + strings.Fields(expected)[0]
+			}
+			return result
+		}
+	}
+
+	if Distance(g, e) <= tolerance(e) {
+		result.Verdict = Typo
+		result.Note = "Almost correct – watch the spelling!"
+		return result
+	}
+
+	return result
+}
+
+// GradeAny grades an answer against every accepted alternative and keeps
+// the best verdict. The first entry is the canonical answer and is used as
+// Expected when nothing comes close.
+func GradeAny(given string, accepted []string) Result {
+	if len(accepted) == 0 {
+		return Result{Given: given}
+	}
+
+	best := Grade(given, accepted[0])
+	for _, answer := range accepted[1:] {
+		result := Grade(given, answer)
+		if rank(result.Verdict) > rank(best.Verdict) {
+			best = result
+		}
+	}
+
+	return best
+}
+
+// rank orders verdicts from worst to best
+func rank(v Verdict) int {
+	switch v {
+	case Correct:
+		return 3
+	case Typo:
+		return 2
+	case WrongArticle:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Normalize lowercases s, collapses whitespace, strips surrounding
+// punctuation and transliterates umlauts and ß to ae/oe/ue/ss
+func Normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsPunct(r) && r != '\''
+	})
+	s = strings.Join(strings.Fields(s), " ")
+	return transliterations.Replace(s)
+}
+
+func foldUmlauts(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = transliterations.Replace(umlautFolds.Replace(s))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// splitArticle separates a leading German article from the rest of a
+// normalized answer
+func splitArticle(s string) (string, string) {
+	parts := strings.SplitN(s, " ", 2)
+	if len(parts) == 2 && articles[parts[0]] {
+		return parts[0], parts[1]
+	}
+	return "", s
+}
+
+func nounMatches(given, expected string) bool {
+	return given == expected || Distance(given, expected) <= tolerance(expected)
+}
+
+// tolerance is how many edits still count as a typo. Very short words must
+// match exactly so that "er" is never accepted for "es".
+func tolerance(s string) int {
+	n := len([]rune(s))
+	switch {
+	case n <= 3:
+		return 0
+	case n <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Distance returns the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
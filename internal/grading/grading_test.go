@@ -0,0 +1,84 @@
+package grading
+
+import "testing"
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		given, expected string
+		want            Verdict
+	}{
+		{"Hallo", "Hallo", Correct},
+		{"  hallo! ", "Hallo", Correct},
+		{"Tschuess", "Tschüss", Correct},
+		{"Tschuss", "Tschüss", Typo},
+		{"Halo", "Hallo", Typo},
+		{"Hnud", "der Hund", WrongArticle},
+		{"Hund", "der Hund", WrongArticle},
+		{"die Hund", "der Hund", WrongArticle},
+		{"der Hund", "der Hund", Correct},
+		{"Katze", "der Hund", Wrong},
+		{"er", "es", Wrong},
+		{"", "Hallo", Wrong},
+	}
+
+	for _, tt := range tests {
+		if got := Grade(tt.given, tt.expected); got.Verdict != tt.want {
+			t.Errorf("Grade(%q, %q) = %v, want %v", tt.given, tt.expected, got.Verdict, tt.want)
+		}
+	}
+}
+
+func TestGradeAny(t *testing.T) {
+	tests := []struct {
+		given        string
+		accepted     []string
+		want         Verdict
+		wantExpected string
+	}{
+		{"they", []string{"she", "they"}, Correct, "they"},
+		{"thay", []string{"she", "they"}, Typo, "they"},
+		{"it", []string{"she", "they"}, Wrong, "she"},
+		{"anything", nil, Wrong, ""},
+	}
+
+	for _, tt := range tests {
+		got := GradeAny(tt.given, tt.accepted)
+		if got.Verdict != tt.want || got.Expected != tt.wantExpected {
+			t.Errorf("GradeAny(%q, %q) = %v %q, want %v %q", tt.given, tt.accepted, got.Verdict, got.Expected, tt.want, tt.wantExpected)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Hallo!", "hallo"},
+		{"  Guten   Morgen. ", "guten morgen"},
+		{"Grüß dich", "gruess dich"},
+		{"'s geht", "'s geht"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"hund", "hund", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"hnud", "hund", 1},
+		{"mädchen", "madchen", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package grading
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Tokens splits a sentence into words, dropping punctuation, for word
+// order exercises
+func Tokens(sentence string) []string {
+	return strings.FieldsFunc(sentence, func(r rune) bool {
+		return unicode.IsSpace(r) || (unicode.IsPunct(r) && r != '\'' && r != '-')
+	})
+}
+
+// GradeOrder compares the order of the given words against every accepted
+// sentence. When the answer is wrong, Misplaced points at the first word
+// that differs from the closest accepted order.
+func GradeOrder(given []string, accepted []string) Result {
+	result := Result{Given: strings.Join(given, " ")}
+	if len(accepted) == 0 {
+		return result
+	}
+	result.Expected = accepted[0]
+
+	best := -1
+	for _, sentence := range accepted {
+		want := Tokens(sentence)
+		prefix := commonPrefix(given, want)
+		if prefix == len(want) && len(given) == len(want) {
+			result.Verdict = Correct
+			result.Expected = sentence
+			result.Misplaced = 0
+			return result
+		}
+
+		// Prefer orders that use the same words, then the longest match
+		score := prefix
+		if sameWords(given, want) {
+			score += len(want) + 1
+		}
+		if score > best {
+			best = score
+			result.Expected = sentence
+			result.Misplaced = prefix + 1
+		}
+	}
+
+	if result.Misplaced > len(given) {
+		result.Note = "Some words are missing"
+	} else {
+		result.Note = "The first misplaced word is \"" + given[result.Misplaced-1] + "\""
+	}
+	return result
+}
+
+func commonPrefix(given, want []string) int {
+	n := 0
+	for n < len(given) && n < len(want) && Normalize(given[n]) == Normalize(want[n]) {
+		n++
+	}
+	return n
+}
+
+// sameWords reports whether a and b contain the same words, ignoring order
+func sameWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	normalized := func(words []string) []string {
+		out := make([]string, len(words))
+		for i, word := range words {
+			out[i] = Normalize(word)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	na, nb := normalized(a), normalized(b)
+	for i := range na {
+		if na[i] != nb[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package grading
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokens(t *testing.T) {
+	got := Tokens("Wie geht's, Hans-Peter?")
+	want := []string{"Wie", "geht's", "Hans-Peter"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokens = %q, want %q", got, want)
+	}
+}
+
+func TestGradeOrder(t *testing.T) {
+	accepted := []string{"Ich gehe heute.", "Heute gehe ich."}
+	tests := []struct {
+		name          string
+		given         []string
+		want          Verdict
+		wantExpected  string
+		wantMisplaced int
+		wantNote      string
+	}{
+		{"first order", []string{"Ich", "gehe", "heute"}, Correct, "Ich gehe heute.", 0, ""},
+		{"second order", []string{"heute", "gehe", "ich"}, Correct, "Heute gehe ich.", 0, ""},
+		{"swapped", []string{"Ich", "heute", "gehe"}, Wrong, "Ich gehe heute.", 2, `The first misplaced word is "heute"`},
+		{"missing word", []string{"Ich", "gehe"}, Wrong, "Ich gehe heute.", 3, "Some words are missing"},
+	}
+
+	for _, tt := range tests {
+		got := GradeOrder(tt.given, accepted)
+		if got.Verdict != tt.want || got.Expected != tt.wantExpected || got.Misplaced != tt.wantMisplaced || got.Note != tt.wantNote {
+			t.Errorf("%s: GradeOrder(%q) = %v %q %d %q, want %v %q %d %q", tt.name, tt.given,
+				got.Verdict, got.Expected, got.Misplaced, got.Note,
+				tt.want, tt.wantExpected, tt.wantMisplaced, tt.wantNote)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Tense is a verb tense the conjugation engine can generate
+type Tense string
+
+const (
+	Present   Tense = "present"
+	Preterite Tense = "präteritum"
+	Perfect   Tense = "perfekt"
+)
+
+// Tenses lists the supported tenses in the order tables show them
+var Tenses = []Tense{Present, Preterite, Perfect}
+
+// Persons are the six grammatical persons, in the order of every form list
+var Persons = []string{"ich", "du", "er/sie/es", "wir", "ihr", "sie/Sie"}
+
+// Title returns the tense name as shown to learners
+func (t Tense) Title() string {
+	switch t {
+	case Present:
+		return "Präsens"
+	case Preterite:
+		return "Präteritum"
+	case Perfect:
+		return "Perfekt"
+	}
+	return string(t)
+}
+
+// ParseTense reads a tense name in English or German
+func ParseTense(name string) (Tense, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "present", "präsens", "praesens":
+		return Present, true
+	case "preterite", "past", "präteritum", "praeteritum", "imperfekt":
+		return Preterite, true
+	case "perfect", "perfekt":
+		return Perfect, true
+	}
+	return "", false
+}
+
+// Conjugation is the full table of a verb
+type Conjugation struct {
+	Infinitive string
+	Prefix     string // Separable prefix, empty for most verbs
+	Auxiliary  string // haben or sein, used to build the Perfekt
+	Participle string
+	Irregular  bool
+	Forms      map[Tense][]string // One form per person, in the order of Persons
+}
+
+// irregularVerb holds the forms that can't be derived from the infinitive
+type irregularVerb struct {
+	present    []string // All six present forms, when the whole present is irregular
+	du, er     string   // Stem-changing present forms, when only these two change
+	past       string   // Präteritum ich/er form
+	participle string
+	auxiliary  string // Defaults to haben
+}
+
+// irregularVerbs lists strong, mixed and modal verbs by infinitive
+var irregularVerbs = map[string]irregularVerb{
+	"sein":      {present: []string{"bin", "bist", "ist", "sind", "seid", "sind"}, past: "war", participle: "gewesen", auxiliary: "sein"},
+	"haben":     {present: []string{"habe", "hast", "hat", "haben", "habt", "haben"}, past: "hatte", participle: "gehabt"},
+	"werden":    {du: "wirst", er: "wird", past: "wurde", participle: "geworden", auxiliary: "sein"},
+	"wissen":    {present: []string{"weiß", "weißt", "weiß", "wissen", "wisst", "wissen"}, past: "wusste", participle: "gewusst"},
+	"tun":       {present: []string{"tue", "tust", "tut", "tun", "tut", "tun"}, past: "tat", participle: "getan"},
+	"können":    {present: []string{"kann", "kannst", "kann", "können", "könnt", "können"}, past: "konnte", participle: "gekonnt"},
+	"müssen":    {present: []string{"muss", "musst", "muss", "müssen", "müsst", "müssen"}, past: "musste", participle: "gemusst"},
+	"wollen":    {present: []string{"will", "willst", "will", "wollen", "wollt", "wollen"}, past: "wollte", participle: "gewollt"},
+	"dürfen":    {present: []string{"darf", "darfst", "darf", "dürfen", "dürft", "dürfen"}, past: "durfte", participle: "gedurft"},
+	"sollen":    {present: []string{"soll", "sollst", "soll", "sollen", "sollt", "sollen"}, past: "sollte", participle: "gesollt"},
+	"mögen":     {present: []string{"mag", "magst", "mag", "mögen", "mögt", "mögen"}, past: "mochte", participle: "gemocht"},
+	"essen":     {du: "isst", er: "isst", past: "aß", participle: "gegessen"},
+	"trinken":   {past: "trank", participle: "getrunken"},
+	"gehen":     {past: "ging", participle: "gegangen", auxiliary: "sein"},
+	"kommen":    {past: "kam", participle: "gekommen", auxiliary: "sein"},
+	"fahren":    {du: "fährst", er: "fährt", past: "fuhr", participle: "gefahren", auxiliary: "sein"},
+	"laufen":    {du: "läufst", er: "läuft", past: "lief", participle: "gelaufen", auxiliary: "sein"},
+	"fliegen":   {past: "flog", participle: "geflogen", auxiliary: "sein"},
+	"schwimmen": {past: "schwamm", participle: "geschwommen", auxiliary: "sein"},
+	"bleiben":   {past: "blieb", participle: "geblieben", auxiliary: "sein"},
+	"steigen":   {past: "stieg", participle: "gestiegen", auxiliary: "sein"},
+	"fallen":    {du: "fällst", er: "fällt", past: "fiel", participle: "gefallen", auxiliary: "sein"},
+	"sterben":   {du: "stirbst", er: "stirbt", past: "starb", participle: "gestorben", auxiliary: "sein"},
+	"sehen":     {du: "siehst", er: "sieht", past: "sah", participle: "gesehen"},
+	"lesen":     {du: "liest", er: "liest", past: "las", participle: "gelesen"},
+	"geben":     {du: "gibst", er: "gibt", past: "gab", participle: "gegeben"},
+	"nehmen":    {du: "nimmst", er: "nimmt", past: "nahm", participle: "genommen"},
+	"sprechen":  {du: "sprichst", er: "spricht", past: "sprach", participle: "gesprochen"},
+	"helfen":    {du: "hilfst", er: "hilft", past: "half", participle: "geholfen"},
+	"treffen":   {du: "triffst", er: "trifft", past: "traf", participle: "getroffen"},
+	"werfen":    {du: "wirfst", er: "wirft", past: "warf", participle: "geworfen"},
+	"vergessen": {du: "vergisst", er: "vergisst", past: "vergaß", participle: "vergessen"},
+	"schlafen":  {du: "schläfst", er: "schläft", past: "schlief", participle: "geschlafen"},
+	"tragen":    {du: "trägst", er: "trägt", past: "trug", participle: "getragen"},
+	"waschen":   {du: "wäschst", er: "wäscht", past: "wusch", participle: "gewaschen"},
+	"halten":    {du: "hältst", er: "hält", past: "hielt", participle: "gehalten"},
+	"fangen":    {du: "fängst", er: "fängt", past: "fing", participle: "gefangen"},
+	"schreiben": {past: "schrieb", participle: "geschrieben"},
+	"finden":    {past: "fand", participle: "gefunden"},
+	"stehen":    {past: "stand", participle: "gestanden"},
+	"sitzen":    {past: "saß", participle: "gesessen"},
+	"liegen":    {past: "lag", participle: "gelegen"},
+	"rufen":     {past: "rief", participle: "gerufen"},
+	"heißen":    {past: "hieß", participle: "geheißen"},
+	"ziehen":    {past: "zog", participle: "gezogen"},
+	"singen":    {past: "sang", participle: "gesungen"},
+	"beginnen":  {past: "begann", participle: "begonnen"},
+	"gewinnen":  {past: "gewann", participle: "gewonnen"},
+	"schließen": {past: "schloss", participle: "geschlossen"},
+	"bringen":   {past: "brachte", participle: "gebracht"},
+	"denken":    {past: "dachte", participle: "gedacht"},
+	"kennen":    {past: "kannte", participle: "gekannt"},
+}
+
+// seinCompounds are compound verbs that take sein although their base verb
+// takes haben
+var seinCompounds = map[string]bool{
+	"aufstehen": true,
+	"umziehen":  true,
+}
+
+// separablePrefixes are detected automatically when the rest of the verb is
+// a verb Conjugate knows, e.g. ankommen, aufstehen or aufmachen
+var separablePrefixes = []string{"zurück", "weiter", "ab", "an", "auf", "aus", "ein", "fern", "los", "mit", "nach", "um", "vor", "weg", "zu"}
+
+// separableBases are common regular verbs that separable verbs are built
+// on. Irregular bases come from irregularVerbs.
+var separableBases = map[string]bool{
+	"arbeiten": true, "bauen": true, "brauchen": true, "decken": true,
+	"drehen": true, "drücken": true, "fassen": true, "führen": true,
+	"füllen": true, "hängen": true, "holen": true, "hören": true,
+	"kaufen": true, "kehren": true, "klären": true, "kochen": true,
+	"lachen": true, "leben": true, "legen": true, "lernen": true,
+	"machen": true, "melden": true, "packen": true, "passen": true,
+	"probieren": true, "räumen": true, "reisen": true, "sagen": true,
+	"schalten": true, "schauen": true, "setzen": true, "spielen": true,
+	"stellen": true, "suchen": true, "teilen": true, "wachen": true,
+	"wählen": true, "wandern": true, "warten": true, "wohnen": true,
+	"zahlen": true, "zeigen": true,
+}
+
+// ErrUnknownPrefix means a verb starts like a separable verb but Conjugate
+// can't tell whether it is one
+var ErrUnknownPrefix = errors.New("separable prefix unknown")
+
+// unseparatedVerbs begin like a separable verb but aren't one
+var unseparatedVerbs = map[string]bool{
+	"abonnieren": true, "analysieren": true, "angeln": true, "antworten": true,
+	"umarmen": true, "umfassen": true, "umgeben": true, "zucken": true,
+	"zupfen": true,
+}
+
+// inseparablePrefixes never take ge- in the participle
+var inseparablePrefixes = []string{"be", "emp", "ent", "er", "ge", "miss", "ver", "zer"}
+
+// inseparableVerbs are regular verbs with an inseparable prefix. A prefix
+// alone can't tell them apart from verbs like bellen or ernten, so weak
+// compounds have to be listed to drop the ge- of their participle.
+var inseparableVerbs = map[string]bool{
+	"beantworten": true, "bedeuten": true, "bedienen": true, "beenden": true,
+	"begleiten": true, "behandeln": true, "behaupten": true, "bemerken": true,
+	"benutzen": true, "berichten": true, "beruhigen": true, "besichtigen": true,
+	"bereiten": true, "besorgen": true, "bestellen": true, "besuchen": true,
+	"bezahlen": true, "entdecken": true, "entschuldigen": true, "entwickeln": true,
+	"erklären": true, "erlauben": true, "erleben": true, "erreichen": true,
+	"erwarten": true, "erzählen": true, "erinnern": true, "erholen": true,
+	"gehören": true, "gewöhnen": true, "missbrauchen": true,
+	"verbessern": true, "verdienen": true, "verkaufen": true, "vermieten": true,
+	"verpassen": true, "versuchen": true, "verändern": true, "verwenden": true,
+	"zerstören": true,
+}
+
+// Conjugate builds the conjugation table of a verb. Separable verbs built
+// on a verb it knows (ankommen, aufmachen) are recognised; for other verbs
+// that start with a separable prefix it returns an error rather than guess,
+// and ConjugateSeparable has to be told the prefix.
+func Conjugate(infinitive string) (Conjugation, error) {
+	infinitive = strings.ToLower(strings.TrimSpace(infinitive))
+	if knownVerb(infinitive) || unseparatedVerbs[infinitive] {
+		return conjugate(infinitive, "")
+	}
+	for _, prefix := range separablePrefixes {
+		base := strings.TrimPrefix(infinitive, prefix)
+		if base == infinitive || len([]rune(base)) < 4 || !strings.HasSuffix(base, "n") {
+			continue
+		}
+		if knownVerb(base) {
+			return conjugate(infinitive, prefix)
+		}
+		return Conjugation{}, fmt.Errorf("%w: is %q separable after %s-?", ErrUnknownPrefix, infinitive, prefix)
+	}
+	return conjugate(infinitive, "")
+}
+
+// knownVerb reports whether a verb without a separable prefix is in one of
+// the tables, directly or behind an inseparable prefix (verstehen)
+func knownVerb(infinitive string) bool {
+	if _, ok := irregularVerbs[infinitive]; ok || separableBases[infinitive] || inseparableVerbs[infinitive] {
+		return true
+	}
+	for _, prefix := range inseparablePrefixes {
+		if base := strings.TrimPrefix(infinitive, prefix); base != infinitive {
+			if _, ok := irregularVerbs[base]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ConjugateSeparable builds the table of a verb with a known separable
+// prefix, such as "auf" in aufmachen. An empty prefix works like Conjugate.
+func ConjugateSeparable(infinitive, prefix string) (Conjugation, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return Conjugate(infinitive)
+	}
+	return conjugate(strings.ToLower(strings.TrimSpace(infinitive)), prefix)
+}
+
+// conjugate builds the table of a lowercased infinitive, splitting off the
+// separable prefix when there is one
+func conjugate(infinitive, prefix string) (Conjugation, error) {
+
+	base := strings.TrimPrefix(infinitive, prefix)
+	if base == "" || !strings.HasSuffix(base, "n") || strings.ContainsAny(base, " ") {
+		return Conjugation{}, fmt.Errorf("%q is not a German infinitive", infinitive)
+	}
+
+	c := conjugateBase(base)
+	c.Infinitive = infinitive
+	c.Prefix = prefix
+
+	if prefix != "" {
+		c.Participle = prefix + c.Participle
+		for _, tense := range []Tense{Present, Preterite} {
+			for i, form := range c.Forms[tense] {
+				c.Forms[tense][i] = form + " " + prefix
+			}
+		}
+	}
+	if seinCompounds[infinitive] {
+		c.Auxiliary = "sein"
+	}
+
+	auxiliary := conjugateBase(c.Auxiliary).Forms[Present]
+	c.Forms[Perfect] = make([]string, len(Persons))
+	for i := range Persons {
+		c.Forms[Perfect][i] = auxiliary[i] + " " + c.Participle
+	}
+
+	return c, nil
+}
+
+// conjugateBase builds the present and Präteritum forms and the participle
+// of a verb without a separable prefix
+func conjugateBase(infinitive string) Conjugation {
+	if verb, ok := irregularVerbs[infinitive]; ok {
+		return conjugateIrregular(infinitive, verb, "")
+	}
+
+	// Inseparable compounds of irregular verbs: verstehen, bekommen
+	for _, prefix := range inseparablePrefixes {
+		base := strings.TrimPrefix(infinitive, prefix)
+		if verb, ok := irregularVerbs[base]; ok && base != infinitive {
+			verb.auxiliary = "haben"
+			return conjugateIrregular(base, verb, prefix)
+		}
+	}
+
+	stem := verbStem(infinitive)
+	c := Conjugation{
+		Auxiliary: "haben",
+		Forms: map[Tense][]string{
+			Present:   regularPresent(infinitive, stem),
+			Preterite: pastForms(stem + linkingE(stem) + "te"),
+		},
+	}
+
+	c.Participle = stem + linkingE(stem) + "t"
+	if takesGe(infinitive) {
+		c.Participle = "ge" + c.Participle
+	}
+	return c
+}
+
+// conjugateIrregular builds the forms of a table verb, optionally with an
+// inseparable prefix in front of every form
+func conjugateIrregular(infinitive string, verb irregularVerb, prefix string) Conjugation {
+	present := verb.present
+	if present == nil {
+		present = regularPresent(infinitive, verbStem(infinitive))
+		if verb.du != "" {
+			present[1] = verb.du
+			present[2] = verb.er
+		}
+	}
+
+	c := Conjugation{
+		Auxiliary:  verb.auxiliary,
+		Participle: verb.participle,
+		Irregular:  true,
+		Forms: map[Tense][]string{
+			Present:   append([]string(nil), present...),
+			Preterite: pastForms(verb.past),
+		},
+	}
+	if c.Auxiliary == "" {
+		c.Auxiliary = "haben"
+	}
+
+	if prefix != "" {
+		c.Participle = prefix + strings.TrimPrefix(c.Participle, "ge")
+		for _, tense := range []Tense{Present, Preterite} {
+			for i, form := range c.Forms[tense] {
+				c.Forms[tense][i] = prefix + form
+			}
+		}
+	}
+	return c
+}
+
+// verbStem drops the infinitive ending: -en, or just -n for -eln and -ern
+// verbs
+func verbStem(infinitive string) string {
+	if strings.HasSuffix(infinitive, "en") {
+		return strings.TrimSuffix(infinitive, "en")
+	}
+	return strings.TrimSuffix(infinitive, "n")
+}
+
+// regularPresent builds the present forms from the stem, handling e
+// insertion (arbeitest), s sounds (tanzt) and -eln/-ern verbs (sammle)
+func regularPresent(infinitive, stem string) []string {
+	e := linkingE(stem)
+
+	ich := stem + "e"
+	if strings.HasSuffix(infinitive, "eln") {
+		ich = strings.TrimSuffix(stem, "el") + "le"
+	}
+
+	du := stem + e + "st"
+	if e == "" && endsWithAny(stem, "sßzx") {
+		du = stem + "t"
+	}
+
+	return []string{ich, du, stem + e + "t", infinitive, stem + e + "t", infinitive}
+}
+
+// pastForms adds the Präteritum endings to the ich/er form. Weak and mixed
+// forms end in -e (machte, hatte); strong ones take their own endings (ging).
+func pastForms(past string) []string {
+	if strings.HasSuffix(past, "e") {
+		return []string{past, past + "st", past, past + "n", past + "t", past + "n"}
+	}
+
+	du, ihr := past+"st", past+"t"
+	switch {
+	case endsWithAny(past, "td"):
+		du, ihr = past+"est", past+"et"
+	case endsWithAny(past, "sßz"):
+		du = past + "est"
+	}
+	return []string{past, du, past, past + "en", ihr, past + "en"}
+}
+
+// linkingE returns "e" when the stem needs one before -st and -t endings:
+// after t or d (arbeitet), and after m or n following another consonant
+// (atmet, öffnet) but not after l, r, h, m or n (lernt)
+func linkingE(stem string) string {
+	runes := []rune(stem)
+	if len(runes) < 2 {
+		return ""
+	}
+
+	last, before := runes[len(runes)-1], runes[len(runes)-2]
+	if last == 't' || last == 'd' {
+		return "e"
+	}
+	if (last == 'm' || last == 'n') && !strings.ContainsRune("aeiouäöülrhmn", before) {
+		return "e"
+	}
+	return ""
+}
+
+// endsWithAny reports whether the last letter of s is one of chars
+func endsWithAny(s, chars string) bool {
+	runes := []rune(s)
+	return len(runes) > 0 && strings.ContainsRune(chars, runes[len(runes)-1])
+}
+
+// takesGe reports whether the participle of a regular verb gets the ge-
+// prefix: not for known inseparable compounds and not for -ieren verbs
+func takesGe(infinitive string) bool {
+	return !inseparableVerbs[infinitive] && !strings.HasSuffix(infinitive, "ieren")
+}
@@ -0,0 +1,104 @@
+package grammar
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConjugate(t *testing.T) {
+	tests := []struct {
+		infinitive string
+		tense      Tense
+		want       []string
+	}{
+		{"machen", Present, []string{"mache", "machst", "macht", "machen", "macht", "machen"}},
+		{"arbeiten", Present, []string{"arbeite", "arbeitest", "arbeitet", "arbeiten", "arbeitet", "arbeiten"}},
+		{"arbeiten", Preterite, []string{"arbeitete", "arbeitetest", "arbeitete", "arbeiteten", "arbeitetet", "arbeiteten"}},
+		{"sein", Present, []string{"bin", "bist", "ist", "sind", "seid", "sind"}},
+		{"fahren", Present, []string{"fahre", "fährst", "fährt", "fahren", "fahrt", "fahren"}},
+		{"fahren", Perfect, []string{"bin gefahren", "bist gefahren", "ist gefahren", "sind gefahren", "seid gefahren", "sind gefahren"}},
+		{"ankommen", Present, []string{"komme an", "kommst an", "kommt an", "kommen an", "kommt an", "kommen an"}},
+		{"aufmachen", Present, []string{"mache auf", "machst auf", "macht auf", "machen auf", "macht auf", "machen auf"}},
+		{"aufmachen", Perfect, []string{"habe aufgemacht", "hast aufgemacht", "hat aufgemacht", "haben aufgemacht", "habt aufgemacht", "haben aufgemacht"}},
+		{"anrufen", Present, []string{"rufe an", "rufst an", "ruft an", "rufen an", "ruft an", "rufen an"}},
+		{"anrufen", Preterite, []string{"rief an", "riefst an", "rief an", "riefen an", "rieft an", "riefen an"}},
+		{"antworten", Present, []string{"antworte", "antwortest", "antwortet", "antworten", "antwortet", "antworten"}},
+		{"vorbereiten", Present, []string{"bereite vor", "bereitest vor", "bereitet vor", "bereiten vor", "bereitet vor", "bereiten vor"}},
+	}
+
+	for _, tt := range tests {
+		c, err := Conjugate(tt.infinitive)
+		if err != nil {
+			t.Errorf("Conjugate(%q): %v", tt.infinitive, err)
+			continue
+		}
+		if got := c.Forms[tt.tense]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Conjugate(%q) %s = %q, want %q", tt.infinitive, tt.tense, got, tt.want)
+		}
+	}
+}
+
+func TestParticiple(t *testing.T) {
+	tests := []struct {
+		infinitive, prefix string
+		want, auxiliary    string
+	}{
+		{"machen", "", "gemacht", "haben"},
+		{"bellen", "", "gebellt", "haben"},
+		{"ernten", "", "geerntet", "haben"},
+		{"bezahlen", "", "bezahlt", "haben"},
+		{"studieren", "", "studiert", "haben"},
+		{"gehen", "", "gegangen", "sein"},
+		{"verstehen", "", "verstanden", "haben"},
+		{"ankommen", "", "angekommen", "sein"},
+		{"aufmachen", "", "aufgemacht", "haben"},
+		{"anrufen", "", "angerufen", "haben"},
+		{"vorbereiten", "", "vorbereitet", "haben"},
+		{"abspülen", "ab", "abgespült", "haben"},
+		{"aufmachen", "auf", "aufgemacht", "haben"},
+	}
+
+	for _, tt := range tests {
+		c, err := ConjugateSeparable(tt.infinitive, tt.prefix)
+		if err != nil {
+			t.Errorf("ConjugateSeparable(%q, %q): %v", tt.infinitive, tt.prefix, err)
+			continue
+		}
+		if c.Participle != tt.want || c.Auxiliary != tt.auxiliary {
+			t.Errorf("%s: participle %q with %s, want %q with %s", tt.infinitive, c.Participle, c.Auxiliary, tt.want, tt.auxiliary)
+		}
+	}
+}
+
+func TestConjugateRejects(t *testing.T) {
+	// abspülen may well be separable, but spülen isn't a verb Conjugate knows
+	for _, word := range []string{"", "Hund", "gut machen", "abspülen"} {
+		if _, err := Conjugate(word); err == nil {
+			t.Errorf("Conjugate(%q) succeeded, want an error", word)
+		}
+	}
+	if _, err := Conjugate("abspülen"); !errors.Is(err, ErrUnknownPrefix) {
+		t.Errorf("Conjugate(abspülen) = %v, want ErrUnknownPrefix", err)
+	}
+}
+
+func TestParseTense(t *testing.T) {
+	tests := []struct {
+		name string
+		want Tense
+		ok   bool
+	}{
+		{"present", Present, true},
+		{" Präsens ", Present, true},
+		{"past", Preterite, true},
+		{"Perfekt", Perfect, true},
+		{"future", "", false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := ParseTense(tt.name); got != tt.want || ok != tt.ok {
+			t.Errorf("ParseTense(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package grammar
+
+import "strings"
+
+// Case is one of the four German grammatical cases
+type Case string
+
+const (
+	Nominative Case = "nominative"
+	Accusative Case = "accusative"
+	Dative     Case = "dative"
+	Genitive   Case = "genitive"
+)
+
+// Cases lists the cases in the order tables show them
+var Cases = []Case{Nominative, Accusative, Dative, Genitive}
+
+// Plural stands in for a gender when declining plural nouns
+const Plural = "plural"
+
+// Title returns the German name of the case
+func (c Case) Title() string {
+	switch c {
+	case Nominative:
+		return "Nominativ"
+	case Accusative:
+		return "Akkusativ"
+	case Dative:
+		return "Dativ"
+	case Genitive:
+		return "Genitiv"
+	}
+	return string(c)
+}
+
+// Abbrev returns the short English label used in exercise prompts
+func (c Case) Abbrev() string {
+	return string(c)[:3] + "."
+}
+
+// ParseCase reads a case name in English or German, or its abbreviation
+func ParseCase(name string) (Case, bool) {
+	switch strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".") {
+	case "nominative", "nominativ", "nom":
+		return Nominative, true
+	case "accusative", "akkusativ", "acc", "akk":
+		return Accusative, true
+	case "dative", "dativ", "dat":
+		return Dative, true
+	case "genitive", "genitiv", "gen":
+		return Genitive, true
+	}
+	return "", false
+}
+
+// ArticleKind is what precedes a noun, which decides the adjective endings
+type ArticleKind int
+
+const (
+	Definite   ArticleKind = iota // der, die, das: weak endings
+	Indefinite                    // ein, eine: mixed endings
+	NoArticle                     // strong endings
+)
+
+// genderColumn maps der, die, das and Plural onto table columns
+func genderColumn(gender string) int {
+	switch gender {
+	case Masculine:
+		return 0
+	case Feminine:
+		return 1
+	case Neuter:
+		return 2
+	}
+	return 3
+}
+
+// Tables are indexed by case, then masculine, feminine, neuter, plural
+var (
+	definiteArticles = map[Case][4]string{
+		Nominative: {"der", "die", "das", "die"},
+		Accusative: {"den", "die", "das", "die"},
+		Dative:     {"dem", "der", "dem", "den"},
+		Genitive:   {"des", "der", "des", "der"},
+	}
+	indefiniteArticles = map[Case][4]string{
+		Nominative: {"ein", "eine", "ein", ""},
+		Accusative: {"einen", "eine", "ein", ""},
+		Dative:     {"einem", "einer", "einem", ""},
+		Genitive:   {"eines", "einer", "eines", ""},
+	}
+	adjectiveEndings = map[ArticleKind]map[Case][4]string{
+		Definite: {
+			Nominative: {"e", "e", "e", "en"},
+			Accusative: {"en", "e", "e", "en"},
+			Dative:     {"en", "en", "en", "en"},
+			Genitive:   {"en", "en", "en", "en"},
+		},
+		// The indefinite plural has no article, so it takes strong endings
+		Indefinite: {
+			Nominative: {"er", "e", "es", "e"},
+			Accusative: {"en", "e", "es", "e"},
+			Dative:     {"en", "en", "en", "en"},
+			Genitive:   {"en", "en", "en", "er"},
+		},
+		NoArticle: {
+			Nominative: {"er", "e", "es", "e"},
+			Accusative: {"en", "e", "es", "e"},
+			Dative:     {"em", "er", "em", "en"},
+			Genitive:   {"en", "er", "en", "er"},
+		},
+	}
+)
+
+// DefiniteArticle returns der, den, dem... for a case and gender
+func DefiniteArticle(c Case, gender string) string {
+	return definiteArticles[c][genderColumn(gender)]
+}
+
+// IndefiniteArticle returns ein, einen, einem... for a case and gender. The
+// plural has no indefinite article and yields "".
+func IndefiniteArticle(c Case, gender string) string {
+	return indefiniteArticles[c][genderColumn(gender)]
+}
+
+// Article returns the article of the given kind, "" for NoArticle
+func Article(kind ArticleKind, c Case, gender string) string {
+	switch kind {
+	case Definite:
+		return DefiniteArticle(c, gender)
+	case Indefinite:
+		return IndefiniteArticle(c, gender)
+	}
+	return ""
+}
+
+// AdjectiveEnding returns the ending of an adjective after an article of the
+// given kind
+func AdjectiveEnding(kind ArticleKind, c Case, gender string) string {
+	return adjectiveEndings[kind][c][genderColumn(gender)]
+}
+
+// weakNouns are masculine nouns of the n-declension, with the ending they
+// take in every case but the nominative
+var weakNouns = map[string]string{
+	"Affe": "n", "Bär": "en", "Bauer": "n", "Buchstabe": "n", "Elefant": "en",
+	"Franzose": "n", "Friede": "n", "Gedanke": "n", "Glaube": "n", "Held": "en",
+	"Herr": "n", "Journalist": "en", "Junge": "n", "Kollege": "n", "Kunde": "n",
+	"Löwe": "n", "Mensch": "en", "Nachbar": "n", "Name": "n", "Polizist": "en",
+	"Präsident": "en", "Prinz": "en", "Russe": "n", "Soldat": "en",
+	"Student": "en", "Tourist": "en", "Wille": "n",
+}
+
+// mixedNouns decline like weak nouns but add an -s in the genitive: des
+// Namens
+var mixedNouns = map[string]bool{
+	"Buchstabe": true, "Friede": true, "Gedanke": true, "Glaube": true,
+	"Name": true, "Wille": true,
+}
+
+// NounForm returns the noun as it appears in a case: masculine and neuter
+// nouns take -s or -es in the genitive, plurals take -n in the dative, and
+// weak masculine nouns take -n or -en outside the nominative
+func NounForm(noun string, c Case, gender string) string {
+	if ending, ok := weakNouns[noun]; ok && gender == Masculine {
+		switch {
+		case c == Nominative:
+			return noun
+		case c == Genitive && mixedNouns[noun]:
+			return noun + ending + "s"
+		}
+		return noun + ending
+	}
+
+	switch {
+	case c == Genitive && (gender == Masculine || gender == Neuter):
+		if endsWithAny(noun, "sßxz") || strings.HasSuffix(noun, "sch") || syllables(noun) == 1 {
+			return noun + "es"
+		}
+		return noun + "s"
+	case c == Dative && gender == Plural:
+		if endsWithAny(noun, "ns") {
+			return noun
+		}
+		return noun + "n"
+	}
+	return noun
+}
+
+// Phrase builds a noun phrase such as "einen kleinen Hund". adjective may
+// be empty.
+func Phrase(kind ArticleKind, c Case, gender, adjective, noun string) string {
+	var words []string
+	if article := Article(kind, c, gender); article != "" {
+		words = append(words, article)
+	}
+	if adjective != "" {
+		// Adjectives ending in -e (müde) don't double it: müden, not müdeen
+		words = append(words, strings.TrimSuffix(adjective, "e")+AdjectiveEnding(kind, c, gender))
+	}
+	return strings.Join(append(words, NounForm(noun, c, gender)), " ")
+}
+
+// syllables counts the vowel groups in a word
+func syllables(word string) int {
+	count := 0
+	inVowel := false
+	for _, r := range strings.ToLower(word) {
+		vowel := strings.ContainsRune("aeiouäöüy", r)
+		if vowel && !inVowel {
+			count++
+		}
+		inVowel = vowel
+	}
+	return count
+}
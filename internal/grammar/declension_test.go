@@ -0,0 +1,79 @@
+package grammar
+
+import "testing"
+
+func TestPhrase(t *testing.T) {
+	tests := []struct {
+		kind              ArticleKind
+		c                 Case
+		gender, adjective string
+		noun, want        string
+	}{
+		{Definite, Nominative, Masculine, "", "Hund", "der Hund"},
+		{Definite, Dative, Feminine, "", "Katze", "der Katze"},
+		{Definite, Genitive, Masculine, "", "Hund", "des Hundes"},
+		{Definite, Genitive, Neuter, "", "Fenster", "des Fensters"},
+		{Definite, Genitive, Neuter, "", "Haus", "des Hauses"},
+		{Definite, Dative, Plural, "", "Kinder", "den Kindern"},
+		{Definite, Dative, Plural, "", "Autos", "den Autos"},
+		{Definite, Nominative, Masculine, "", "Name", "der Name"},
+		{Definite, Accusative, Masculine, "", "Name", "den Namen"},
+		{Definite, Dative, Masculine, "", "Name", "dem Namen"},
+		{Definite, Genitive, Masculine, "", "Name", "des Namens"},
+		{Definite, Accusative, Masculine, "", "Herr", "den Herrn"},
+		{Definite, Genitive, Masculine, "", "Herr", "des Herrn"},
+		{Definite, Dative, Masculine, "", "Junge", "dem Jungen"},
+		{Indefinite, Accusative, Masculine, "neu", "Student", "einen neuen Studenten"},
+		{Definite, Genitive, Masculine, "", "Mensch", "des Menschen"},
+		{Definite, Dative, Plural, "", "Menschen", "den Menschen"},
+		{Indefinite, Accusative, Masculine, "klein", "Hund", "einen kleinen Hund"},
+		{Indefinite, Nominative, Neuter, "müde", "Kind", "ein müdes Kind"},
+		{Indefinite, Nominative, Plural, "gut", "Freunde", "gute Freunde"},
+		{NoArticle, Dative, Feminine, "kalt", "Milch", "kalter Milch"},
+	}
+
+	for _, tt := range tests {
+		if got := Phrase(tt.kind, tt.c, tt.gender, tt.adjective, tt.noun); got != tt.want {
+			t.Errorf("Phrase(%v, %s, %s, %q, %q) = %q, want %q", tt.kind, tt.c, tt.gender, tt.adjective, tt.noun, got, tt.want)
+		}
+	}
+}
+
+func TestParseCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want Case
+		ok   bool
+	}{
+		{"dative", Dative, true},
+		{"Akkusativ", Accusative, true},
+		{"gen.", Genitive, true},
+		{" NOM ", Nominative, true},
+		{"vocative", "", false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := ParseCase(tt.name); got != tt.want || ok != tt.ok {
+			t.Errorf("ParseCase(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSplitArticle(t *testing.T) {
+	tests := []struct {
+		german, article, noun string
+		ok                    bool
+	}{
+		{"der Hund", "der", "Hund", true},
+		{"Die Katze", "die", "Katze", true},
+		{"Hund", "", "Hund", false},
+		{"ein Hund", "", "ein Hund", false},
+	}
+
+	for _, tt := range tests {
+		article, noun, ok := SplitArticle(tt.german)
+		if article != tt.article || noun != tt.noun || ok != tt.ok {
+			t.Errorf("SplitArticle(%q) = %q, %q, %v; want %q, %q, %v", tt.german, article, noun, ok, tt.article, tt.noun, tt.ok)
+		}
+	}
+}
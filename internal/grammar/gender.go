@@ -0,0 +1,44 @@
+package grammar
+
+import "strings"
+
+// Definite articles in the nominative, one per grammatical gender
+const (
+	Masculine = "der"
+	Feminine  = "die"
+	Neuter    = "das"
+)
+
+// Genders lists the nominative articles in the order they are offered
+var Genders = []string{Masculine, Feminine, Neuter}
+
+// SplitArticle splits a noun stored as "der Hund" into its article and the
+// bare noun. ok is false when the entry does not start with der, die or das.
+func SplitArticle(german string) (article, noun string, ok bool) {
+	fields := strings.Fields(german)
+	if len(fields) < 2 {
+		return "", german, false
+	}
+
+	article = strings.ToLower(fields[0])
+	for _, gender := range Genders {
+		if article == gender {
+			return article, strings.Join(fields[1:], " "), true
+		}
+	}
+	return "", german, false
+}
+
+// ParseGender reads a gender answer given as an article (der), a number in
+// the order of Genders (1) or a letter (m, f, n)
+func ParseGender(answer string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "der", "1", "m":
+		return Masculine, true
+	case "die", "2", "f":
+		return Feminine, true
+	case "das", "3", "n":
+		return Neuter, true
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package hearts
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/settings"
+	"duocli/internal/store"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// Max is the number of hearts a learner can hold
+	Max = 5
+
+	// RegenInterval is how long it takes to regain a single heart
+	RegenInterval = 30 * time.Minute
+)
+
+// Enabled reports whether the user plays with hearts
+func Enabled(s store.SettingStore, userID uint) bool {
+	return settings.Enabled(s, userID, settings.Hearts)
+}
+
+// Refresh adds any hearts regenerated since the user's last update. It only
+// changes the struct; callers save it.
+func Refresh(user *models.User, now time.Time) {
+	if user.Hearts >= Max {
+		user.Hearts = Max
+		return
+	}
+
+	if user.HeartsUpdatedAt.IsZero() {
+		user.HeartsUpdatedAt = now
+		return
+	}
+
+	regained := int(now.Sub(user.HeartsUpdatedAt) / RegenInterval)
+	if regained <= 0 {
+		return
+	}
+
+	user.Hearts += regained
+	user.HeartsUpdatedAt = user.HeartsUpdatedAt.Add(time.Duration(regained) * RegenInterval)
+	if user.Hearts >= Max {
+		user.Hearts = Max
+		user.HeartsUpdatedAt = now
+	}
+}
+
+// Lose takes a heart from the user and saves the change straight away so
+// quitting mid-lesson can't undo it
+func Lose(s store.UserStore, user *models.User) error {
+	now := time.Now()
+	Refresh(user, now)
+	if user.Hearts == Max {
+		user.HeartsUpdatedAt = now
+	}
+	if user.Hearts > 0 {
+		user.Hearts--
+	}
+	return s.SaveUser(user)
+}
+
+// Gain gives the user up to n hearts back, capped at Max
+func Gain(s store.UserStore, user *models.User, n int) error {
+	Refresh(user, time.Now())
+	user.Hearts += n
+	if user.Hearts > Max {
+		user.Hearts = Max
+	}
+	return s.SaveUser(user)
+}
+
+// NextIn returns how long until the user regains their next heart, or zero
+// when they are full
+func NextIn(user models.User, now time.Time) time.Duration {
+	if user.Hearts >= Max || user.HeartsUpdatedAt.IsZero() {
+		return 0
+	}
+	return user.HeartsUpdatedAt.Add(RegenInterval).Sub(now)
+}
+
+// FormatWait renders a regeneration wait in whole minutes
+func FormatWait(d time.Duration) string {
+	return fmt.Sprintf("%d min", int(math.Ceil(d.Minutes())))
+}
+
+// Display renders hearts as a row of filled and empty icons
+func Display(hearts int) string {
+	if hearts < 0 {
+		hearts = 0
+	}
+	if hearts > Max {
+		hearts = Max
+	}
+	return strings.Repeat("❤️", hearts) + strings.Repeat("🖤", Max-hearts)
+}
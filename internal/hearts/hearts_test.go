@@ -0,0 +1,126 @@
+package hearts
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/settings"
+	"duocli/internal/store"
+	"testing"
+	"time"
+)
+
+func TestRefresh(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		hearts      int
+		updated     time.Time
+		wantHearts  int
+		wantUpdated time.Time
+	}{
+		{"full", Max, now.Add(-time.Hour), Max, now.Add(-time.Hour)},
+		{"over full", Max + 2, now.Add(-time.Hour), Max, now.Add(-time.Hour)},
+		{"never updated", 2, time.Time{}, 2, now},
+		{"not yet", 2, now.Add(-RegenInterval + time.Minute), 2, now.Add(-RegenInterval + time.Minute)},
+		{"one heart", 2, now.Add(-RegenInterval - time.Minute), 3, now.Add(-time.Minute)},
+		{"two hearts", 0, now.Add(-2*RegenInterval - time.Minute), 2, now.Add(-time.Minute)},
+		{"refilled", 3, now.Add(-10 * RegenInterval), Max, now},
+	}
+
+	for _, tt := range tests {
+		user := models.User{Hearts: tt.hearts, HeartsUpdatedAt: tt.updated}
+		Refresh(&user, now)
+		if user.Hearts != tt.wantHearts || !user.HeartsUpdatedAt.Equal(tt.wantUpdated) {
+			t.Errorf("%s: Refresh = %d hearts updated %s, want %d updated %s",
+				tt.name, user.Hearts, user.HeartsUpdatedAt, tt.wantHearts, tt.wantUpdated)
+		}
+	}
+}
+
+func TestLoseGain(t *testing.T) {
+	s := store.NewMemory()
+	user := models.User{Name: "tester"}
+	if err := s.CreateUser(&user); err != nil {
+		t.Fatal(err)
+	}
+
+	// Losing the first heart starts the regeneration clock
+	before := time.Now()
+	if err := Lose(s, &user); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := s.User(user.ID)
+	if saved.Hearts != Max-1 || saved.HeartsUpdatedAt.Before(before) {
+		t.Errorf("after Lose: %d hearts updated %s, want %d updated now", saved.Hearts, saved.HeartsUpdatedAt, Max-1)
+	}
+
+	for i := 0; i < Max+2; i++ {
+		if err := Lose(s, &user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if saved, _ := s.User(user.ID); saved.Hearts != 0 {
+		t.Errorf("after losing them all: %d hearts, want 0", saved.Hearts)
+	}
+
+	if err := Gain(s, &user, 2); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := s.User(user.ID); saved.Hearts != 2 {
+		t.Errorf("after Gain(2): %d hearts, want 2", saved.Hearts)
+	}
+	if err := Gain(s, &user, Max); err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := s.User(user.ID); saved.Hearts != Max {
+		t.Errorf("after Gain(Max): %d hearts, want %d", saved.Hearts, Max)
+	}
+}
+
+func TestNextIn(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := NextIn(models.User{Hearts: Max, HeartsUpdatedAt: now}, now); got != 0 {
+		t.Errorf("NextIn(full) = %s, want 0", got)
+	}
+	user := models.User{Hearts: 1, HeartsUpdatedAt: now.Add(-10 * time.Minute)}
+	if got := NextIn(user, now); got != RegenInterval-10*time.Minute {
+		t.Errorf("NextIn = %s, want %s", got, RegenInterval-10*time.Minute)
+	}
+	if got := FormatWait(19*time.Minute + time.Second); got != "20 min" {
+		t.Errorf("FormatWait = %q, want 20 min", got)
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	tests := []struct {
+		hearts int
+		want   string
+	}{
+		{3, "❤️❤️❤️🖤🖤"},
+		{0, "🖤🖤🖤🖤🖤"},
+		{-1, "🖤🖤🖤🖤🖤"},
+		{Max + 1, "❤️❤️❤️❤️❤️"},
+	}
+	for _, tt := range tests {
+		if got := Display(tt.hearts); got != tt.want {
+			t.Errorf("Display(%d) = %q, want %q", tt.hearts, got, tt.want)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	s := store.NewMemory()
+	if !Enabled(s, 1) {
+		t.Error("Enabled = false by default, want true")
+	}
+	if err := s.SetSetting(1, settings.Hearts, "off"); err != nil {
+		t.Fatal(err)
+	}
+	if Enabled(s, 1) {
+		t.Error("Enabled = true after turning hearts off")
+	}
+	if !Enabled(s, 2) {
+		t.Error("another user's setting turned hearts off")
+	}
+}
@@ -6,41 +6,69 @@ import (
 
 // User represents a learner
 type User struct {
-	ID        uint      `gorm:"primarykey" json:"id"`
-	Name      string    `json:"name"`
-	Level     int       `json:"level" gorm:"default:1"`
-	XP        int       `json:"xp" gorm:"default:0"`
-	Streak    int       `json:"streak" gorm:"default:0"`
-	LastSeen  time.Time `json:"last_seen"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uint      `gorm:"primarykey" json:"id"`
+	Name            string    `json:"name"`
+	Level           int       `json:"level" gorm:"default:1"`
+	XP              int       `json:"xp" gorm:"default:0"`
+	Streak          int       `json:"streak" gorm:"default:0"`
+	Hearts          int       `json:"hearts" gorm:"default:5"`
+	HeartsUpdatedAt time.Time `json:"hearts_updated_at"` // Regeneration counts from here
+	LastSeen        time.Time `json:"last_seen"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // Lesson represents a language lesson
 type Lesson struct {
 	ID          uint   `gorm:"primarykey" json:"id"`
+	Key         string `json:"key" gorm:"index"` // Stable content-pack key
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Level       int    `json:"level"`
 	Order       int    `json:"order"`
 	XPReward    int    `json:"xp_reward" gorm:"default:10"`
-	IsCompleted bool   `json:"is_completed" gorm:"default:false"`
 	Language    string `json:"language" gorm:"default:german"`
 }
 
+// Lesson states for UserLesson
+const (
+	LessonInProgress = "in_progress"
+	LessonCompleted  = "completed"
+)
+
+// UserLesson tracks a single learner's state for a lesson
+type UserLesson struct {
+	ID               uint       `gorm:"primarykey" json:"id"`
+	UserID           uint       `json:"user_id" gorm:"uniqueIndex:idx_user_lesson"`
+	LessonID         uint       `json:"lesson_id" gorm:"uniqueIndex:idx_user_lesson"`
+	Status           string     `json:"status"` // in_progress, completed
+	BestScore        float64    `json:"best_score"`
+	Attempts         int        `json:"attempts"`
+	FirstCompletedAt *time.Time `json:"first_completed_at"`
+	LastCompletedAt  *time.Time `json:"last_completed_at"`
+	User             User       `gorm:"foreignKey:UserID"`
+	Lesson           Lesson     `gorm:"foreignKey:LessonID"`
+}
+
 // Exercise represents individual exercises within lessons
 type Exercise struct {
 	ID           uint   `gorm:"primarykey" json:"id"`
 	LessonID     uint   `json:"lesson_id"`
-	Type         string `json:"type"` // translation, multiple_choice, fill_blank, speaking
+	Key          string `json:"key" gorm:"index"` // Stable content-pack key
+	Type         string `json:"type"`             // translation, multiple_choice, fill_blank, word_order, match_pairs, conjugation, declension, speaking
 	Question     string `json:"question"`
-	Answer       string `json:"answer"`
-	Options      string `json:"options"` // JSON string for multiple choice
+	Answer       string `json:"answer"`       // Canonical answer shown to the learner
+	Parameter    string `json:"parameter"`    // Generated exercises: the tense, case or category to ask about
+	Alternatives string `json:"alternatives"` // JSON string of other accepted answers
+	Options      string `json:"options"`      // JSON string for multiple choice
 	Hint         string `json:"hint"`
 	Explanation  string `json:"explanation"`
 	Order        int    `json:"order"`
 	Difficulty   int    `json:"difficulty" gorm:"default:1"`
 	Lesson       Lesson `gorm:"foreignKey:LessonID"`
+
+	// Vocabulary lists the words the exercise practises
+	Vocabulary []Vocabulary `gorm:"many2many:exercise_vocabulary;" json:"vocabulary,omitempty"`
 }
 
 // Progress tracks user progress
@@ -50,6 +78,7 @@ type Progress struct {
 	LessonID    uint      `json:"lesson_id"`
 	ExerciseID  uint      `json:"exercise_id"`
 	IsCorrect   bool      `json:"is_correct"`
+	Credit      float64   `json:"credit"` // Share answered correctly, for partial credit exercises
 	Attempts    int       `json:"attempts" gorm:"default:1"`
 	CompletedAt time.Time `json:"completed_at"`
 	User        User      `gorm:"foreignKey:UserID"`
@@ -57,14 +86,87 @@ type Progress struct {
 	Exercise    Exercise  `gorm:"foreignKey:ExerciseID"`
 }
 
-// Vocabulary represents words to learn
+// Vocabulary represents words to learn. Nouns are stored without their
+// article, which lives in Gender.
 type Vocabulary struct {
-	ID          uint   `gorm:"primarykey" json:"id"`
-	German      string `json:"german"`
-	English     string `json:"english"`
-	Category    string `json:"category"`
-	Difficulty  int    `json:"difficulty" gorm:"default:1"`
-	AudioURL    string `json:"audio_url"`
-	Example     string `json:"example"`
-	Translation string `json:"translation"`
-}
\ No newline at end of file
+	ID              uint   `gorm:"primarykey" json:"id"`
+	Key             string `json:"key" gorm:"index"` // Stable content-pack key
+	German          string `json:"german"`
+	English         string `json:"english"`
+	Category        string `json:"category"`
+	Difficulty      int    `json:"difficulty" gorm:"default:1"`
+	PartOfSpeech    string `json:"part_of_speech"` // noun, verb, adjective, pronoun, phrase
+	Gender          string `json:"gender"`         // Nouns only: der, die, das
+	Plural          string `json:"plural"`         // Nouns only, without article
+	Past            string `json:"past"`           // Verbs only: Präteritum, e.g. ging
+	Participle      string `json:"participle"`     // Verbs only: Partizip II, e.g. gegangen
+	SeparablePrefix string `json:"separable_prefix"`
+	IPA             string `json:"ipa"`
+	AudioURL        string `json:"audio_url"`
+	Example         string `json:"example"`
+	Translation     string `json:"translation"`
+}
+
+// WithArticle returns the word as learners type it: nouns with their
+// article, everything else as stored
+func (v Vocabulary) WithArticle() string {
+	if v.Gender == "" {
+		return v.German
+	}
+	return v.Gender + " " + v.German
+}
+
+// ReviewItem holds spaced-repetition state for a single exercise or
+// vocabulary word, per user
+type ReviewItem struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	UserID         uint      `json:"user_id" gorm:"uniqueIndex:idx_review_item"`
+	ItemType       string    `json:"item_type" gorm:"uniqueIndex:idx_review_item"` // exercise, vocabulary
+	ItemID         uint      `json:"item_id" gorm:"uniqueIndex:idx_review_item"`
+	EaseFactor     float64   `json:"ease_factor" gorm:"default:2.5"`
+	Interval       int       `json:"interval"` // days
+	Repetitions    int       `json:"repetitions"`
+	Lapses         int       `json:"lapses"`
+	DueAt          time.Time `json:"due_at" gorm:"index"`
+	LastReviewedAt time.Time `json:"last_reviewed_at"`
+}
+
+// Setting is a key/value preference. UserID 0 marks an app-wide setting.
+type Setting struct {
+	ID     uint   `gorm:"primarykey" json:"id"`
+	UserID uint   `json:"user_id" gorm:"uniqueIndex:idx_setting"`
+	Key    string `json:"key" gorm:"uniqueIndex:idx_setting"`
+	Value  string `json:"value"`
+}
+
+// WordStrength is how well a user knows a word, from 0 to 1. It rises with
+// correct answers and decays from LastSeenAt on.
+type WordStrength struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	UserID       uint      `json:"user_id" gorm:"uniqueIndex:idx_word_strength"`
+	VocabularyID uint      `json:"vocabulary_id" gorm:"uniqueIndex:idx_word_strength"`
+	Strength     float64   `json:"strength"`
+	Answers      int       `json:"answers"`
+	Correct      int       `json:"correct"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+}
+
+// VocabAnswer records one answer in vocabulary practice
+type VocabAnswer struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	UserID       uint      `json:"user_id" gorm:"index"`
+	VocabularyID uint      `json:"vocabulary_id"`
+	Mode         string    `json:"mode"` // de-en, en-de, cloze
+	Correct      bool      `json:"correct"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// GenderAnswer records one answer in the noun gender drill
+type GenderAnswer struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	UserID       uint      `json:"user_id" gorm:"index"`
+	VocabularyID uint      `json:"vocabulary_id"`
+	Expected     string    `json:"expected"` // der, die, das
+	Given        string    `json:"given"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,103 @@
+package progress
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"sort"
+	"time"
+)
+
+// Confusion is how often nouns of one gender were given another article
+type Confusion struct {
+	Expected string
+	Given    string
+	Count    int
+	Total    int // Answers for nouns of the expected gender
+}
+
+// Rate returns the confusion as a percentage of the expected gender's answers
+func (c Confusion) Rate() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(c.Count) / float64(c.Total) * 100
+}
+
+// RecordGender stores an answer from the gender drill
+func RecordGender(s store.ProgressStore, userID, vocabularyID uint, expected, given string) error {
+	return s.AddGenderAnswer(&models.GenderAnswer{
+		UserID:       userID,
+		VocabularyID: vocabularyID,
+		Expected:     expected,
+		Given:        given,
+		CreatedAt:    time.Now(),
+	})
+}
+
+// NounGenders returns the user's gender accuracy per noun, keyed by
+// vocabulary ID
+func NounGenders(s store.ProgressStore, userID uint) map[uint]WordResult {
+	answers, _ := s.GenderAnswers(userID)
+
+	stats := map[uint]WordResult{}
+	for _, answer := range answers {
+		stat := stats[answer.VocabularyID]
+		stat.VocabularyID = answer.VocabularyID
+		stat.Answers++
+		if answer.Expected == answer.Given {
+			stat.Correct++
+		}
+		stats[answer.VocabularyID] = stat
+	}
+	return stats
+}
+
+// GenderConfusions returns every expected/given pair of different articles
+// the user has answered, with the total answers for the expected gender
+func GenderConfusions(s store.ProgressStore, userID uint) []Confusion {
+	answers, _ := s.GenderAnswers(userID)
+	totals := genderTotals(answers)
+
+	counts := map[[2]string]int{}
+	for _, answer := range answers {
+		if answer.Expected != answer.Given {
+			counts[[2]string{answer.Expected, answer.Given}]++
+		}
+	}
+
+	confusions := make([]Confusion, 0, len(counts))
+	for pair, count := range counts {
+		confusions = append(confusions, Confusion{
+			Expected: pair[0],
+			Given:    pair[1],
+			Count:    count,
+			Total:    totals[pair[0]].Answers,
+		})
+	}
+	sort.Slice(confusions, func(i, j int) bool {
+		if confusions[i].Expected != confusions[j].Expected {
+			return confusions[i].Expected < confusions[j].Expected
+		}
+		return confusions[i].Given < confusions[j].Given
+	})
+	return confusions
+}
+
+// GenderTotals returns the user's answers and correct answers per gender
+func GenderTotals(s store.ProgressStore, userID uint) map[string]WordResult {
+	answers, _ := s.GenderAnswers(userID)
+	return genderTotals(answers)
+}
+
+func genderTotals(answers []models.GenderAnswer) map[string]WordResult {
+	totals := map[string]WordResult{}
+	for _, answer := range answers {
+		total := totals[answer.Expected]
+		total.Answers++
+		if answer.Expected == answer.Given {
+			total.Correct++
+		}
+		totals[answer.Expected] = total
+	}
+	return totals
+}
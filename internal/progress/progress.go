@@ -0,0 +1,95 @@
+package progress
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"errors"
+	"time"
+)
+
+// LessonState returns the user's state for a lesson. A lesson the user has
+// never attempted yields a zero UserLesson with an empty Status.
+func LessonState(s store.ProgressStore, userID, lessonID uint) models.UserLesson {
+	state, _ := s.LessonState(userID, lessonID)
+	return state
+}
+
+// IsCompleted reports whether the user has passed the lesson
+func IsCompleted(s store.ProgressStore, userID, lessonID uint) bool {
+	return LessonState(s, userID, lessonID).Status == models.LessonCompleted
+}
+
+// IsUnlocked reports whether the user may start the lesson: the first lesson
+// of a course is always open, later ones need the previous lesson of the same
+// course completed
+func IsUnlocked(s store.Store, userID uint, lesson models.Lesson) bool {
+	if lesson.Order == 1 {
+		return true
+	}
+
+	prevLesson, err := s.LessonByOrder(lesson.Language, lesson.Order-1)
+	if err != nil {
+		return false
+	}
+
+	return IsCompleted(s, userID, prevLesson.ID)
+}
+
+// CompletedCount returns how many of lessons the user has completed
+func CompletedCount(s store.ProgressStore, userID uint, lessons []models.Lesson) int64 {
+	states, _ := s.LessonStates(userID)
+
+	completed := make(map[uint]bool)
+	for _, state := range states {
+		if state.Status == models.LessonCompleted {
+			completed[state.LessonID] = true
+		}
+	}
+
+	var count int64
+	for _, lesson := range lessons {
+		if completed[lesson.ID] {
+			count++
+		}
+	}
+	return count
+}
+
+// AnsweredToday returns how many lesson exercises the user has finished
+// since midnight
+func AnsweredToday(s store.ProgressStore, userID uint) int64 {
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	rows, _ := s.ProgressSince(userID, midnight)
+	return int64(len(rows))
+}
+
+// RecordAttempt stores the outcome of a lesson attempt. A passed attempt
+// marks the lesson completed; a failed one never downgrades a completion.
+func RecordAttempt(s store.ProgressStore, userID, lessonID uint, score float64, passed bool) error {
+	state, err := s.LessonState(userID, lessonID)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	state.UserID = userID
+	state.LessonID = lessonID
+	state.Attempts++
+
+	if score > state.BestScore {
+		state.BestScore = score
+	}
+
+	if passed {
+		now := time.Now()
+		state.Status = models.LessonCompleted
+		if state.FirstCompletedAt == nil {
+			state.FirstCompletedAt = &now
+		}
+		state.LastCompletedAt = &now
+	} else if state.Status == "" {
+		state.Status = models.LessonInProgress
+	}
+
+	return s.SaveLessonState(&state)
+}
@@ -0,0 +1,40 @@
+package progress
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"testing"
+)
+
+func TestCompletedCount(t *testing.T) {
+	s := store.NewMemory()
+	var lessons []models.Lesson
+	for i, course := range []string{"german", "german", "spanish"} {
+		lesson := models.Lesson{Title: course, Order: i + 1, Language: course}
+		if err := s.SaveLesson(&lesson); err != nil {
+			t.Fatal(err)
+		}
+		lessons = append(lessons, lesson)
+		state := models.UserLesson{UserID: 1, LessonID: lesson.ID, Status: models.LessonCompleted}
+		if i == 1 {
+			state.Status = models.LessonInProgress
+		}
+		if err := s.SaveLessonState(&state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	german, err := s.Lessons("german")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := CompletedCount(s, 1, german); got != 1 {
+		t.Errorf("CompletedCount(german) = %d, want 1", got)
+	}
+	if got := CompletedCount(s, 1, lessons); got != 2 {
+		t.Errorf("CompletedCount(all) = %d, want 2", got)
+	}
+	if got := CompletedCount(s, 2, lessons); got != 0 {
+		t.Errorf("CompletedCount for another user = %d, want 0", got)
+	}
+}
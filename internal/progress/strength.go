@@ -0,0 +1,70 @@
+package progress
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	// StrengthHalfLife is how long it takes an unpractised word to lose
+	// half its strength
+	StrengthHalfLife = 7 * 24 * time.Hour
+
+	// strengthGain is the share of the remaining gap to 1 that a correct
+	// answer closes; a wrong answer halves the strength
+	strengthGain = 0.35
+
+	// Thresholds for the weak and mastered vocabulary views
+	WeakStrength     = 0.5
+	MasteredStrength = 0.8
+)
+
+// CurrentStrength returns a word's strength at now, after decay
+func CurrentStrength(ws models.WordStrength, now time.Time) float64 {
+	if ws.LastSeenAt.IsZero() {
+		return ws.Strength
+	}
+	elapsed := now.Sub(ws.LastSeenAt)
+	if elapsed <= 0 {
+		return ws.Strength
+	}
+	return ws.Strength * math.Pow(0.5, float64(elapsed)/float64(StrengthHalfLife))
+}
+
+// RecordStrength updates the user's strength on a word after an answer
+func RecordStrength(s store.ProgressStore, userID, vocabularyID uint, correct bool, now time.Time) error {
+	ws, err := s.WordStrength(userID, vocabularyID)
+	if errors.Is(err, store.ErrNotFound) {
+		ws = models.WordStrength{UserID: userID, VocabularyID: vocabularyID}
+	} else if err != nil {
+		return err
+	}
+
+	strength := CurrentStrength(ws, now)
+	ws.Answers++
+	if correct {
+		ws.Correct++
+		strength += (1 - strength) * strengthGain
+	} else {
+		strength /= 2
+	}
+	ws.Strength = strength
+	ws.LastSeenAt = now
+
+	return s.SaveWordStrength(&ws)
+}
+
+// Strengths returns the user's current strength on every word they have
+// answered, keyed by vocabulary ID
+func Strengths(s store.ProgressStore, userID uint, now time.Time) map[uint]float64 {
+	rows, _ := s.WordStrengths(userID)
+
+	strengths := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		strengths[row.VocabularyID] = CurrentStrength(row, now)
+	}
+	return strengths
+}
@@ -0,0 +1,92 @@
+package progress
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCurrentStrength(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		ws   models.WordStrength
+		want float64
+	}{
+		{"never seen", models.WordStrength{Strength: 0.6}, 0.6},
+		{"just now", models.WordStrength{Strength: 0.6, LastSeenAt: now}, 0.6},
+		{"in the future", models.WordStrength{Strength: 0.6, LastSeenAt: now.Add(time.Hour)}, 0.6},
+		{"one half-life", models.WordStrength{Strength: 0.8, LastSeenAt: now.Add(-StrengthHalfLife)}, 0.4},
+		{"two half-lives", models.WordStrength{Strength: 0.8, LastSeenAt: now.Add(-2 * StrengthHalfLife)}, 0.2},
+		{"half a half-life", models.WordStrength{Strength: 1, LastSeenAt: now.Add(-StrengthHalfLife / 2)}, math.Sqrt(0.5)},
+	}
+
+	for _, tt := range tests {
+		if got := CurrentStrength(tt.ws, now); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: CurrentStrength = %.4f, want %.4f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecordStrength(t *testing.T) {
+	s := store.NewMemory()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		at      time.Time
+		correct bool
+		want    float64
+	}{
+		{now, true, 0.35},
+		{now, true, 0.35 + 0.65*0.35},
+		{now, false, (0.35 + 0.65*0.35) / 2},
+		// A week later half has decayed before the answer counts
+		{now.Add(StrengthHalfLife), true, (0.35+0.65*0.35)/4 + (1-(0.35+0.65*0.35)/4)*0.35},
+	}
+
+	for i, step := range steps {
+		if err := RecordStrength(s, 1, 7, step.correct, step.at); err != nil {
+			t.Fatal(err)
+		}
+		ws, err := s.WordStrength(1, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(ws.Strength-step.want) > 1e-9 || !ws.LastSeenAt.Equal(step.at) {
+			t.Errorf("step %d: strength %.4f seen %s, want %.4f seen %s", i, ws.Strength, ws.LastSeenAt, step.want, step.at)
+		}
+	}
+
+	ws, _ := s.WordStrength(1, 7)
+	if ws.Answers != 4 || ws.Correct != 3 {
+		t.Errorf("%d answers, %d correct; want 4 and 3", ws.Answers, ws.Correct)
+	}
+	if rows, _ := s.WordStrengths(1); len(rows) != 1 {
+		t.Errorf("got %d strength rows, want 1", len(rows))
+	}
+}
+
+func TestStrengths(t *testing.T) {
+	s := store.NewMemory()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, ws := range []models.WordStrength{
+		{UserID: 1, VocabularyID: 1, Strength: 0.9, LastSeenAt: now},
+		{UserID: 1, VocabularyID: 2, Strength: 0.9, LastSeenAt: now.Add(-StrengthHalfLife)},
+		{UserID: 2, VocabularyID: 3, Strength: 0.9, LastSeenAt: now},
+	} {
+		if err := s.SaveWordStrength(&ws); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := Strengths(s, 1, now)
+	if len(got) != 2 || math.Abs(got[1]-0.9) > 1e-9 || math.Abs(got[2]-0.45) > 1e-9 {
+		t.Errorf("Strengths = %v, want 1: 0.9 and 2: 0.45", got)
+	}
+	if got[2] >= WeakStrength || got[1] < MasteredStrength {
+		t.Errorf("Strengths = %v, want word 2 weak and word 1 mastered", got)
+	}
+}
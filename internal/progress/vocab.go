@@ -0,0 +1,52 @@
+package progress
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"time"
+)
+
+// WordResult is a learner's record on one word, in vocabulary practice or
+// on its gender in the gender drill
+type WordResult struct {
+	VocabularyID uint
+	Answers      int
+	Correct      int
+}
+
+// Accuracy returns the share of correct answers as a percentage
+func (w WordResult) Accuracy() float64 {
+	if w.Answers == 0 {
+		return 0
+	}
+	return float64(w.Correct) / float64(w.Answers) * 100
+}
+
+// RecordVocab stores an answer from vocabulary practice
+func RecordVocab(s store.ProgressStore, userID, vocabularyID uint, mode string, correct bool) error {
+	return s.AddVocabAnswer(&models.VocabAnswer{
+		UserID:       userID,
+		VocabularyID: vocabularyID,
+		Mode:         mode,
+		Correct:      correct,
+		CreatedAt:    time.Now(),
+	})
+}
+
+// WordResults returns the user's practice results per word, keyed by
+// vocabulary ID
+func WordResults(s store.ProgressStore, userID uint) map[uint]WordResult {
+	answers, _ := s.VocabAnswers(userID)
+
+	results := map[uint]WordResult{}
+	for _, answer := range answers {
+		result := results[answer.VocabularyID]
+		result.VocabularyID = answer.VocabularyID
+		result.Answers++
+		if answer.Correct {
+			result.Correct++
+		}
+		results[answer.VocabularyID] = result
+	}
+	return results
+}
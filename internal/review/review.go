@@ -0,0 +1,103 @@
+package review
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"errors"
+	"math"
+	"time"
+)
+
+// Item types tracked by the scheduler
+const (
+	ItemExercise   = "exercise"
+	ItemVocabulary = "vocabulary"
+)
+
+// Answer quality on the SM-2 0-5 scale
+const (
+	QualityWrong   = 1
+	QualityHard    = 3
+	QualityCorrect = 4
+	QualityPerfect = 5
+)
+
+const (
+	defaultEase = 2.5
+	minEase     = 1.3
+)
+
+// Schedule applies the SM-2 algorithm to item for an answer of the given
+// quality and sets its next due date relative to now
+func Schedule(item *models.ReviewItem, quality int, now time.Time) {
+	if item.EaseFactor == 0 {
+		item.EaseFactor = defaultEase
+	}
+
+	if quality < QualityHard {
+		item.Repetitions = 0
+		item.Interval = 1
+		item.Lapses++
+	} else {
+		item.Repetitions++
+		switch item.Repetitions {
+		case 1:
+			item.Interval = 1
+		case 2:
+			item.Interval = 6
+		default:
+			item.Interval = int(math.Round(float64(item.Interval) * item.EaseFactor))
+		}
+	}
+
+	q := float64(5 - quality)
+	item.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if item.EaseFactor < minEase {
+		item.EaseFactor = minEase
+	}
+
+	item.LastReviewedAt = now
+	item.DueAt = now.AddDate(0, 0, item.Interval)
+}
+
+// Record feeds a graded answer into the scheduler, creating the review item
+// the first time it is seen
+func Record(s store.ReviewStore, userID uint, itemType string, itemID uint, quality int) error {
+	item, err := s.ReviewItem(userID, itemType, itemID)
+	if errors.Is(err, store.ErrNotFound) {
+		item = models.ReviewItem{UserID: userID, ItemType: itemType, ItemID: itemID, EaseFactor: defaultEase}
+	} else if err != nil {
+		return err
+	}
+
+	Schedule(&item, quality, time.Now())
+	return s.SaveReviewItem(&item)
+}
+
+// PractisedWords returns the IDs of the vocabulary an exercise practises.
+// Exercises without linked words fall back to the word their answer
+// corresponds to, if there is one.
+func PractisedWords(s store.Store, exercise models.Exercise) ([]uint, error) {
+	words, err := s.ExerciseWords(exercise.ID)
+	if err != nil || len(words) > 0 || exercise.Answer == "" {
+		return words, err
+	}
+
+	vocab, err := s.Words(store.WordQuery{German: exercise.Answer})
+	if err != nil || len(vocab) == 0 {
+		return nil, err // Not every exercise maps to a word
+	}
+	return []uint{vocab[0].ID}, nil
+}
+
+// Due returns the user's review items that are due now, most overdue first.
+// A limit of zero or less returns every due item.
+func Due(s store.ReviewStore, userID uint, limit int) ([]models.ReviewItem, error) {
+	return s.DueReviewItems(userID, time.Now(), limit)
+}
+
+// DueCount returns how many review items are due for the user
+func DueCount(s store.ReviewStore, userID uint) int64 {
+	items, _ := s.DueReviewItems(userID, time.Now(), 0)
+	return int64(len(items))
+}
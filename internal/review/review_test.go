@@ -0,0 +1,63 @@
+package review
+
+import (
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		name         string
+		qualities    []int
+		wantReps     int
+		wantInterval int
+		wantEase     float64
+		wantLapses   int
+	}{
+		{"first correct", []int{QualityCorrect}, 1, 1, 2.5, 0},
+		{"second correct", []int{QualityCorrect, QualityCorrect}, 2, 6, 2.5, 0},
+		{"third correct", []int{QualityCorrect, QualityCorrect, QualityCorrect}, 3, 15, 2.5, 0},
+		{"perfect raises ease", []int{QualityPerfect}, 1, 1, 2.6, 0},
+		{"hard lowers ease", []int{QualityHard}, 1, 1, 2.36, 0},
+		{"miss resets", []int{QualityCorrect, QualityCorrect, QualityWrong}, 0, 1, 1.96, 1},
+		{"ease floor", []int{QualityWrong, QualityWrong, QualityWrong, QualityWrong}, 0, 1, 1.3, 4},
+	}
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		var item models.ReviewItem
+		for _, q := range tt.qualities {
+			Schedule(&item, q, now)
+		}
+		if item.Repetitions != tt.wantReps || item.Interval != tt.wantInterval || item.Lapses != tt.wantLapses ||
+			math.Abs(item.EaseFactor-tt.wantEase) > 1e-9 {
+			t.Errorf("%s: got reps %d, interval %d, ease %.2f, lapses %d; want %d, %d, %.2f, %d", tt.name,
+				item.Repetitions, item.Interval, item.EaseFactor, item.Lapses,
+				tt.wantReps, tt.wantInterval, tt.wantEase, tt.wantLapses)
+		}
+		if want := now.AddDate(0, 0, tt.wantInterval); !item.DueAt.Equal(want) {
+			t.Errorf("%s: due %v, want %v", tt.name, item.DueAt, want)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	s := store.NewMemory()
+
+	for i := 0; i < 2; i++ {
+		if err := Record(s, 1, ItemVocabulary, 7, QualityCorrect); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	item, err := s.ReviewItem(1, ItemVocabulary, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Repetitions != 2 || item.Interval != 6 {
+		t.Errorf("got reps %d, interval %d; want 2, 6", item.Repetitions, item.Interval)
+	}
+}
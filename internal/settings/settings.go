@@ -0,0 +1,86 @@
+package settings
+
+import (
+	"duocli/internal/store"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Well-known setting keys
+const (
+	ActiveProfile = "active_profile"
+	Hearts        = "hearts"
+	Choices       = "choices"       // Number of multiple choice options
+	ChoiceLabels  = "choice_labels" // numbers or letters
+)
+
+// UserDefaults lists the per-user settings learners can change, with their
+// default values
+var UserDefaults = map[string]string{
+	Hearts:       "on",
+	Choices:      "4",
+	ChoiceLabels: "numbers",
+}
+
+// allowedValues lists the values each user setting accepts
+var allowedValues = map[string][]string{
+	Hearts:       {"on", "off"},
+	Choices:      {"2", "3", "4", "5", "6"},
+	ChoiceLabels: {"numbers", "letters"},
+}
+
+// Validate checks that value is allowed for key
+func Validate(key, value string) error {
+	allowed := allowedValues[key]
+	for _, v := range allowed {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be one of: %s", key, strings.Join(allowed, ", "))
+}
+
+// Get returns the value stored for key, or "" when it is unset. Pass a
+// userID of 0 for app-wide settings.
+func Get(s store.SettingStore, userID uint, key string) string {
+	value, err := s.Setting(userID, key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// GetOrDefault returns the user's value for key, falling back to its entry
+// in UserDefaults
+func GetOrDefault(s store.SettingStore, userID uint, key string) string {
+	if value := Get(s, userID, key); value != "" {
+		return value
+	}
+	return UserDefaults[key]
+}
+
+// Int returns a numeric setting for the user, falling back to its default
+// when the stored value is not a number
+func Int(s store.SettingStore, userID uint, key string) int {
+	if n, err := strconv.Atoi(GetOrDefault(s, userID, key)); err == nil {
+		return n
+	}
+	n, _ := strconv.Atoi(UserDefaults[key])
+	return n
+}
+
+// Enabled reports whether an on/off setting is switched on for the user
+func Enabled(s store.SettingStore, userID uint, key string) bool {
+	return GetOrDefault(s, userID, key) == "on"
+}
+
+// Set stores value under key, replacing any previous value
+func Set(s store.SettingStore, userID uint, key, value string) error {
+	return s.SetSetting(userID, key, value)
+}
+
+// Delete removes key
+func Delete(s store.SettingStore, userID uint, key string) error {
+	return s.DeleteSetting(userID, key)
+}
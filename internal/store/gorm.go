@@ -0,0 +1,312 @@
+package store
+
+import (
+	"duocli/internal/models"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Gorm is a Store backed by a GORM database
+type Gorm struct {
+	DB *gorm.DB
+}
+
+// NewGorm wraps an open, migrated database
+func NewGorm(db *gorm.DB) *Gorm {
+	return &Gorm{DB: db}
+}
+
+func (s *Gorm) Transaction(fn func(tx Store) error) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(NewGorm(tx))
+	})
+}
+
+// first loads a single row into dest, mapping a missing row to ErrNotFound
+func first(query *gorm.DB, dest interface{}) error {
+	err := query.First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *Gorm) User(id uint) (models.User, error) {
+	var user models.User
+	return user, first(s.DB.Where("id = ?", id), &user)
+}
+
+func (s *Gorm) UserByName(name string) (models.User, error) {
+	var user models.User
+	return user, first(s.DB.Where("LOWER(name) = ?", strings.ToLower(name)), &user)
+}
+
+func (s *Gorm) Users() ([]models.User, error) {
+	var users []models.User
+	return users, s.DB.Order("id").Find(&users).Error
+}
+
+func (s *Gorm) CreateUser(user *models.User) error {
+	return s.DB.Create(user).Error
+}
+
+func (s *Gorm) SaveUser(user *models.User) error {
+	return s.DB.Save(user).Error
+}
+
+func (s *Gorm) DeleteUser(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := resetProgress(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.Setting{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, id).Error
+	})
+}
+
+func (s *Gorm) Lesson(id uint) (models.Lesson, error) {
+	var lesson models.Lesson
+	return lesson, first(s.DB.Where("id = ?", id), &lesson)
+}
+
+func (s *Gorm) LessonByOrder(course string, order int) (models.Lesson, error) {
+	var lesson models.Lesson
+	return lesson, first(s.DB.Where("language = ? AND \"order\" = ?", course, order), &lesson)
+}
+
+func (s *Gorm) Lessons(course string) ([]models.Lesson, error) {
+	query := s.DB.Order("\"order\"")
+	if course != "" {
+		query = query.Where("language = ?", course)
+	}
+	var lessons []models.Lesson
+	return lessons, query.Find(&lessons).Error
+}
+
+func (s *Gorm) SaveLesson(lesson *models.Lesson) error {
+	return s.DB.Save(lesson).Error
+}
+
+func (s *Gorm) DeleteLesson(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&models.Exercise{}).Where("lesson_id = ?", id).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		for _, exerciseID := range ids {
+			if err := deleteExercise(tx, exerciseID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("lesson_id = ?", id).Delete(&models.UserLesson{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Lesson{}, id).Error
+	})
+}
+
+func (s *Gorm) Exercise(id uint) (models.Exercise, error) {
+	var exercise models.Exercise
+	return exercise, first(s.DB.Where("id = ?", id), &exercise)
+}
+
+func (s *Gorm) LessonExercises(lessonID uint) ([]models.Exercise, error) {
+	var exercises []models.Exercise
+	return exercises, s.DB.Where("lesson_id = ?", lessonID).Order("\"order\"").Find(&exercises).Error
+}
+
+func (s *Gorm) ExerciseWords(exerciseID uint) ([]uint, error) {
+	var ids []uint
+	err := s.DB.Table("exercise_vocabulary").
+		Where("exercise_id = ?", exerciseID).
+		Order("vocabulary_id").
+		Pluck("vocabulary_id", &ids).Error
+	return ids, err
+}
+
+func (s *Gorm) SaveExercise(exercise *models.Exercise) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Lesson", "Vocabulary").Save(exercise).Error; err != nil {
+			return err
+		}
+		if exercise.Vocabulary == nil {
+			return nil
+		}
+		return tx.Model(exercise).Association("Vocabulary").Replace(exercise.Vocabulary)
+	})
+}
+
+func (s *Gorm) DeleteExercise(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return deleteExercise(tx, id)
+	})
+}
+
+// deleteExercise removes an exercise, its vocabulary links and its review
+// schedule. Progress rows stay, as the answers were still given.
+func deleteExercise(tx *gorm.DB, id uint) error {
+	if err := tx.Exec("DELETE FROM exercise_vocabulary WHERE exercise_id = ?", id).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("item_type = ? AND item_id = ?", itemExercise, id).Delete(&models.ReviewItem{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.Exercise{}, id).Error
+}
+
+func (s *Gorm) Word(id uint) (models.Vocabulary, error) {
+	var word models.Vocabulary
+	return word, first(s.DB.Where("id = ?", id), &word)
+}
+
+func (s *Gorm) Words(q WordQuery) ([]models.Vocabulary, error) {
+	query := s.DB.Order("id")
+	if q.Category != "" {
+		query = query.Where("category = ?", q.Category)
+	}
+	if len(q.Keys) > 0 {
+		query = query.Where("\"key\" IN ?", q.Keys)
+	}
+	if q.German != "" {
+		query = query.Where("LOWER(german) = ?", strings.ToLower(q.German))
+	}
+	if q.PartOfSpeech != "" {
+		query = query.Where("part_of_speech = ?", q.PartOfSpeech)
+	}
+	if q.Nouns {
+		query = query.Where("gender <> ''")
+	}
+
+	var words []models.Vocabulary
+	return words, query.Find(&words).Error
+}
+
+func (s *Gorm) SaveWord(word *models.Vocabulary) error {
+	return s.DB.Save(word).Error
+}
+
+func (s *Gorm) AddProgress(progress *models.Progress) error {
+	return s.DB.Create(progress).Error
+}
+
+func (s *Gorm) ProgressSince(userID uint, since time.Time) ([]models.Progress, error) {
+	query := s.DB.Where("user_id = ?", userID).Order("id")
+	if !since.IsZero() {
+		query = query.Where("completed_at >= ?", since)
+	}
+	var rows []models.Progress
+	return rows, query.Find(&rows).Error
+}
+
+func (s *Gorm) ResetProgress(userID uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return resetProgress(tx, userID)
+	})
+}
+
+// resetProgress deletes everything recorded for a user except the profile
+// and their settings
+func resetProgress(tx *gorm.DB, userID uint) error {
+	for _, model := range []interface{}{
+		&models.Progress{},
+		&models.ReviewItem{},
+		&models.UserLesson{},
+		&models.GenderAnswer{},
+		&models.VocabAnswer{},
+		&models.WordStrength{},
+	} {
+		if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Gorm) LessonState(userID, lessonID uint) (models.UserLesson, error) {
+	var state models.UserLesson
+	return state, first(s.DB.Where("user_id = ? AND lesson_id = ?", userID, lessonID), &state)
+}
+
+func (s *Gorm) LessonStates(userID uint) ([]models.UserLesson, error) {
+	var states []models.UserLesson
+	return states, s.DB.Where("user_id = ?", userID).Order("id").Find(&states).Error
+}
+
+func (s *Gorm) SaveLessonState(state *models.UserLesson) error {
+	return s.DB.Save(state).Error
+}
+
+func (s *Gorm) AddGenderAnswer(answer *models.GenderAnswer) error {
+	return s.DB.Create(answer).Error
+}
+
+func (s *Gorm) GenderAnswers(userID uint) ([]models.GenderAnswer, error) {
+	var answers []models.GenderAnswer
+	return answers, s.DB.Where("user_id = ?", userID).Order("id").Find(&answers).Error
+}
+
+func (s *Gorm) AddVocabAnswer(answer *models.VocabAnswer) error {
+	return s.DB.Create(answer).Error
+}
+
+func (s *Gorm) VocabAnswers(userID uint) ([]models.VocabAnswer, error) {
+	var answers []models.VocabAnswer
+	return answers, s.DB.Where("user_id = ?", userID).Order("id").Find(&answers).Error
+}
+
+func (s *Gorm) WordStrength(userID, vocabularyID uint) (models.WordStrength, error) {
+	var strength models.WordStrength
+	return strength, first(s.DB.Where("user_id = ? AND vocabulary_id = ?", userID, vocabularyID), &strength)
+}
+
+func (s *Gorm) WordStrengths(userID uint) ([]models.WordStrength, error) {
+	var strengths []models.WordStrength
+	return strengths, s.DB.Where("user_id = ?", userID).Order("id").Find(&strengths).Error
+}
+
+func (s *Gorm) SaveWordStrength(strength *models.WordStrength) error {
+	return s.DB.Save(strength).Error
+}
+
+func (s *Gorm) ReviewItem(userID uint, itemType string, itemID uint) (models.ReviewItem, error) {
+	var item models.ReviewItem
+	query := s.DB.Where("user_id = ? AND item_type = ? AND item_id = ?", userID, itemType, itemID)
+	return item, first(query, &item)
+}
+
+func (s *Gorm) DueReviewItems(userID uint, now time.Time, limit int) ([]models.ReviewItem, error) {
+	query := s.DB.Where("user_id = ? AND due_at <= ?", userID, now).Order("due_at")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var items []models.ReviewItem
+	return items, query.Find(&items).Error
+}
+
+func (s *Gorm) SaveReviewItem(item *models.ReviewItem) error {
+	return s.DB.Save(item).Error
+}
+
+func (s *Gorm) Setting(userID uint, key string) (string, error) {
+	var setting models.Setting
+	err := first(s.DB.Where("user_id = ? AND \"key\" = ?", userID, key), &setting)
+	return setting.Value, err
+}
+
+func (s *Gorm) SetSetting(userID uint, key, value string) error {
+	var setting models.Setting
+	if err := s.DB.Where(models.Setting{UserID: userID, Key: key}).FirstOrInit(&setting).Error; err != nil {
+		return err
+	}
+	setting.Value = value
+	return s.DB.Save(&setting).Error
+}
+
+func (s *Gorm) DeleteSetting(userID uint, key string) error {
+	return s.DB.Where("user_id = ? AND \"key\" = ?", userID, key).Delete(&models.Setting{}).Error
+}
@@ -0,0 +1,577 @@
+package store
+
+import (
+	"duocli/internal/models"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory is a Store that keeps everything in process. Nothing survives the
+// process; it suits tests.
+type Memory struct {
+	mu sync.Mutex
+
+	users         map[uint]models.User
+	lessons       map[uint]models.Lesson
+	exercises     map[uint]models.Exercise
+	exerciseWords map[uint][]uint
+	words         map[uint]models.Vocabulary
+	progress      []models.Progress
+	lessonStates  map[uint]models.UserLesson
+	genderAnswers []models.GenderAnswer
+	vocabAnswers  []models.VocabAnswer
+	strengths     map[uint]models.WordStrength
+	reviewItems   map[uint]models.ReviewItem
+	settings      map[settingKey]string
+
+	lastID uint
+}
+
+type settingKey struct {
+	userID uint
+	key    string
+}
+
+// NewMemory returns an empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{
+		users:         map[uint]models.User{},
+		lessons:       map[uint]models.Lesson{},
+		exercises:     map[uint]models.Exercise{},
+		exerciseWords: map[uint][]uint{},
+		words:         map[uint]models.Vocabulary{},
+		lessonStates:  map[uint]models.UserLesson{},
+		strengths:     map[uint]models.WordStrength{},
+		reviewItems:   map[uint]models.ReviewItem{},
+		settings:      map[settingKey]string{},
+	}
+}
+
+// nextID hands out IDs like an autoincrement column. One counter serves
+// every table, which is fine as IDs only need to be unique per table.
+func (m *Memory) nextID() uint {
+	m.lastID++
+	return m.lastID
+}
+
+// assign gives a new record an ID, or keeps track of one chosen by the caller
+func (m *Memory) assign(id *uint) {
+	if *id == 0 {
+		*id = m.nextID()
+	} else if *id > m.lastID {
+		m.lastID = *id
+	}
+}
+
+// Transaction restores the store's previous contents if fn fails. Unlike a
+// database transaction it does not keep other writers out while fn runs.
+func (m *Memory) Transaction(fn func(tx Store) error) error {
+	m.mu.Lock()
+	saved := m.snapshot()
+	m.mu.Unlock()
+
+	if err := fn(m); err != nil {
+		m.mu.Lock()
+		m.restore(saved)
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// snapshot copies the store's contents. Stored values are never modified in
+// place, so shallow copies are enough.
+func (m *Memory) snapshot() *Memory {
+	return &Memory{
+		users:         maps.Clone(m.users),
+		lessons:       maps.Clone(m.lessons),
+		exercises:     maps.Clone(m.exercises),
+		exerciseWords: maps.Clone(m.exerciseWords),
+		words:         maps.Clone(m.words),
+		progress:      slices.Clone(m.progress),
+		lessonStates:  maps.Clone(m.lessonStates),
+		genderAnswers: slices.Clone(m.genderAnswers),
+		vocabAnswers:  slices.Clone(m.vocabAnswers),
+		strengths:     maps.Clone(m.strengths),
+		reviewItems:   maps.Clone(m.reviewItems),
+		settings:      maps.Clone(m.settings),
+		lastID:        m.lastID,
+	}
+}
+
+// restore puts back contents taken by snapshot
+func (m *Memory) restore(saved *Memory) {
+	m.users, m.lessons, m.exercises = saved.users, saved.lessons, saved.exercises
+	m.exerciseWords, m.words = saved.exerciseWords, saved.words
+	m.progress, m.lessonStates = saved.progress, saved.lessonStates
+	m.genderAnswers, m.vocabAnswers = saved.genderAnswers, saved.vocabAnswers
+	m.strengths, m.reviewItems, m.settings = saved.strengths, saved.reviewItems, saved.settings
+	m.lastID = saved.lastID
+}
+
+// sortedByID returns the values of a map in ID order
+func sortedByID[T any](items map[uint]T, keep func(T) bool) []T {
+	ids := make([]uint, 0, len(items))
+	for id, item := range items {
+		if keep == nil || keep(item) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	list := make([]T, len(ids))
+	for i, id := range ids {
+		list[i] = items[id]
+	}
+	return list
+}
+
+func (m *Memory) User(id uint) (models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[id]
+	if !ok {
+		return user, ErrNotFound
+	}
+	return user, nil
+}
+
+func (m *Memory) UserByName(name string) (models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range sortedByID(m.users, nil) {
+		if strings.EqualFold(user.Name, name) {
+			return user, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+func (m *Memory) Users() ([]models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedByID(m.users, nil), nil
+}
+
+func (m *Memory) CreateUser(user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&user.ID)
+	now := time.Now()
+	user.CreatedAt, user.UpdatedAt = now, now
+	// Match the column defaults of the SQL schema
+	if user.Level == 0 {
+		user.Level = 1
+	}
+	if user.Hearts == 0 {
+		user.Hearts = 5
+	}
+	m.users[user.ID] = *user
+	return nil
+}
+
+func (m *Memory) SaveUser(user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&user.ID)
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	user.UpdatedAt = time.Now()
+	m.users[user.ID] = *user
+	return nil
+}
+
+func (m *Memory) DeleteUser(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resetProgress(id)
+	for key := range m.settings {
+		if key.userID == id {
+			delete(m.settings, key)
+		}
+	}
+	delete(m.users, id)
+	return nil
+}
+
+func (m *Memory) Lesson(id uint) (models.Lesson, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lesson, ok := m.lessons[id]
+	if !ok {
+		return lesson, ErrNotFound
+	}
+	return lesson, nil
+}
+
+func (m *Memory) LessonByOrder(course string, order int) (models.Lesson, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, lesson := range sortedByID(m.lessons, nil) {
+		if lesson.Language == course && lesson.Order == order {
+			return lesson, nil
+		}
+	}
+	return models.Lesson{}, ErrNotFound
+}
+
+func (m *Memory) Lessons(course string) ([]models.Lesson, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lessons := sortedByID(m.lessons, func(l models.Lesson) bool {
+		return course == "" || l.Language == course
+	})
+	sort.SliceStable(lessons, func(i, j int) bool { return lessons[i].Order < lessons[j].Order })
+	return lessons, nil
+}
+
+func (m *Memory) SaveLesson(lesson *models.Lesson) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&lesson.ID)
+	if lesson.Language == "" {
+		lesson.Language = "german"
+	}
+	m.lessons[lesson.ID] = *lesson
+	return nil
+}
+
+func (m *Memory) DeleteLesson(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for exerciseID, exercise := range m.exercises {
+		if exercise.LessonID == id {
+			m.deleteExercise(exerciseID)
+		}
+	}
+	for stateID, state := range m.lessonStates {
+		if state.LessonID == id {
+			delete(m.lessonStates, stateID)
+		}
+	}
+	delete(m.lessons, id)
+	return nil
+}
+
+func (m *Memory) Exercise(id uint) (models.Exercise, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exercise, ok := m.exercises[id]
+	if !ok {
+		return exercise, ErrNotFound
+	}
+	return exercise, nil
+}
+
+func (m *Memory) LessonExercises(lessonID uint) ([]models.Exercise, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exercises := sortedByID(m.exercises, func(e models.Exercise) bool { return e.LessonID == lessonID })
+	sort.SliceStable(exercises, func(i, j int) bool { return exercises[i].Order < exercises[j].Order })
+	return exercises, nil
+}
+
+func (m *Memory) ExerciseWords(exerciseID uint) ([]uint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]uint(nil), m.exerciseWords[exerciseID]...), nil
+}
+
+func (m *Memory) SaveExercise(exercise *models.Exercise) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&exercise.ID)
+
+	if exercise.Vocabulary != nil {
+		ids := make([]uint, 0, len(exercise.Vocabulary))
+		for _, word := range exercise.Vocabulary {
+			ids = append(ids, word.ID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		m.exerciseWords[exercise.ID] = ids
+	}
+
+	stored := *exercise
+	stored.Lesson = models.Lesson{}
+	stored.Vocabulary = nil
+	m.exercises[exercise.ID] = stored
+	return nil
+}
+
+func (m *Memory) DeleteExercise(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteExercise(id)
+	return nil
+}
+
+func (m *Memory) deleteExercise(id uint) {
+	for itemID, item := range m.reviewItems {
+		if item.ItemType == itemExercise && item.ItemID == id {
+			delete(m.reviewItems, itemID)
+		}
+	}
+	delete(m.exerciseWords, id)
+	delete(m.exercises, id)
+}
+
+func (m *Memory) Word(id uint) (models.Vocabulary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	word, ok := m.words[id]
+	if !ok {
+		return word, ErrNotFound
+	}
+	return word, nil
+}
+
+func (m *Memory) Words(q WordQuery) ([]models.Vocabulary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := map[string]bool{}
+	for _, key := range q.Keys {
+		keys[key] = true
+	}
+	return sortedByID(m.words, func(w models.Vocabulary) bool {
+		return (q.Category == "" || w.Category == q.Category) &&
+			(len(q.Keys) == 0 || keys[w.Key]) &&
+			(q.German == "" || strings.EqualFold(w.German, q.German)) &&
+			(q.PartOfSpeech == "" || w.PartOfSpeech == q.PartOfSpeech) &&
+			(!q.Nouns || w.Gender != "")
+	}), nil
+}
+
+func (m *Memory) SaveWord(word *models.Vocabulary) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&word.ID)
+	m.words[word.ID] = *word
+	return nil
+}
+
+func (m *Memory) AddProgress(progress *models.Progress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&progress.ID)
+	m.progress = append(m.progress, *progress)
+	return nil
+}
+
+func (m *Memory) ProgressSince(userID uint, since time.Time) ([]models.Progress, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows []models.Progress
+	for _, p := range m.progress {
+		if p.UserID == userID && !p.CompletedAt.Before(since) {
+			rows = append(rows, p)
+		}
+	}
+	return rows, nil
+}
+
+func (m *Memory) ResetProgress(userID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resetProgress(userID)
+	return nil
+}
+
+func (m *Memory) resetProgress(userID uint) {
+	progress := m.progress[:0]
+	for _, p := range m.progress {
+		if p.UserID != userID {
+			progress = append(progress, p)
+		}
+	}
+	m.progress = progress
+
+	genders := m.genderAnswers[:0]
+	for _, a := range m.genderAnswers {
+		if a.UserID != userID {
+			genders = append(genders, a)
+		}
+	}
+	m.genderAnswers = genders
+
+	vocab := m.vocabAnswers[:0]
+	for _, a := range m.vocabAnswers {
+		if a.UserID != userID {
+			vocab = append(vocab, a)
+		}
+	}
+	m.vocabAnswers = vocab
+
+	for id, state := range m.lessonStates {
+		if state.UserID == userID {
+			delete(m.lessonStates, id)
+		}
+	}
+	for id, strength := range m.strengths {
+		if strength.UserID == userID {
+			delete(m.strengths, id)
+		}
+	}
+	for id, item := range m.reviewItems {
+		if item.UserID == userID {
+			delete(m.reviewItems, id)
+		}
+	}
+}
+
+func (m *Memory) LessonState(userID, lessonID uint) (models.UserLesson, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, state := range sortedByID(m.lessonStates, nil) {
+		if state.UserID == userID && state.LessonID == lessonID {
+			return state, nil
+		}
+	}
+	return models.UserLesson{}, ErrNotFound
+}
+
+func (m *Memory) LessonStates(userID uint) ([]models.UserLesson, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedByID(m.lessonStates, func(s models.UserLesson) bool { return s.UserID == userID }), nil
+}
+
+func (m *Memory) SaveLessonState(state *models.UserLesson) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&state.ID)
+	m.lessonStates[state.ID] = *state
+	return nil
+}
+
+func (m *Memory) AddGenderAnswer(answer *models.GenderAnswer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&answer.ID)
+	if answer.CreatedAt.IsZero() {
+		answer.CreatedAt = time.Now()
+	}
+	m.genderAnswers = append(m.genderAnswers, *answer)
+	return nil
+}
+
+func (m *Memory) GenderAnswers(userID uint) ([]models.GenderAnswer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var answers []models.GenderAnswer
+	for _, a := range m.genderAnswers {
+		if a.UserID == userID {
+			answers = append(answers, a)
+		}
+	}
+	return answers, nil
+}
+
+func (m *Memory) AddVocabAnswer(answer *models.VocabAnswer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&answer.ID)
+	if answer.CreatedAt.IsZero() {
+		answer.CreatedAt = time.Now()
+	}
+	m.vocabAnswers = append(m.vocabAnswers, *answer)
+	return nil
+}
+
+func (m *Memory) VocabAnswers(userID uint) ([]models.VocabAnswer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var answers []models.VocabAnswer
+	for _, a := range m.vocabAnswers {
+		if a.UserID == userID {
+			answers = append(answers, a)
+		}
+	}
+	return answers, nil
+}
+
+func (m *Memory) WordStrength(userID, vocabularyID uint) (models.WordStrength, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, strength := range sortedByID(m.strengths, nil) {
+		if strength.UserID == userID && strength.VocabularyID == vocabularyID {
+			return strength, nil
+		}
+	}
+	return models.WordStrength{}, ErrNotFound
+}
+
+func (m *Memory) WordStrengths(userID uint) ([]models.WordStrength, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedByID(m.strengths, func(s models.WordStrength) bool { return s.UserID == userID }), nil
+}
+
+func (m *Memory) SaveWordStrength(strength *models.WordStrength) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&strength.ID)
+	m.strengths[strength.ID] = *strength
+	return nil
+}
+
+func (m *Memory) ReviewItem(userID uint, itemType string, itemID uint) (models.ReviewItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, item := range sortedByID(m.reviewItems, nil) {
+		if item.UserID == userID && item.ItemType == itemType && item.ItemID == itemID {
+			return item, nil
+		}
+	}
+	return models.ReviewItem{}, ErrNotFound
+}
+
+func (m *Memory) DueReviewItems(userID uint, now time.Time, limit int) ([]models.ReviewItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := sortedByID(m.reviewItems, func(r models.ReviewItem) bool {
+		return r.UserID == userID && !r.DueAt.After(now)
+	})
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DueAt.Before(items[j].DueAt) })
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+func (m *Memory) SaveReviewItem(item *models.ReviewItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.assign(&item.ID)
+	m.reviewItems[item.ID] = *item
+	return nil
+}
+
+func (m *Memory) Setting(userID uint, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.settings[settingKey{userID, key}]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (m *Memory) SetSetting(userID uint, key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.settings[settingKey{userID, key}] = value
+	return nil
+}
+
+func (m *Memory) DeleteSetting(userID uint, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.settings, settingKey{userID, key})
+	return nil
+}
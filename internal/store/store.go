@@ -0,0 +1,111 @@
+// Package store defines where DuoCLI keeps lessons, vocabulary, learners and
+// their progress. Gorm is backed by a SQL database; Memory keeps everything
+// in process, for tests.
+package store
+
+import (
+	"duocli/internal/models"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// itemExercise is the review item type of exercises, review.ItemExercise,
+// which deleting an exercise has to clear
+const itemExercise = "exercise"
+
+// Store is everything DuoCLI reads and writes between runs
+type Store interface {
+	UserStore
+	LessonStore
+	VocabularyStore
+	ProgressStore
+	ReviewStore
+	SettingStore
+
+	// Transaction runs fn against a store whose writes are kept only if fn
+	// returns nil
+	Transaction(fn func(tx Store) error) error
+}
+
+// UserStore keeps learner profiles
+type UserStore interface {
+	User(id uint) (models.User, error)
+	UserByName(name string) (models.User, error) // Case-insensitive
+	Users() ([]models.User, error)               // In ID order
+	CreateUser(user *models.User) error
+	SaveUser(user *models.User) error
+	DeleteUser(id uint) error // Along with everything recorded for the user
+}
+
+// LessonStore keeps lessons and their exercises
+type LessonStore interface {
+	Lesson(id uint) (models.Lesson, error)
+	LessonByOrder(course string, order int) (models.Lesson, error)
+	Lessons(course string) ([]models.Lesson, error) // In order; "" for every course
+	SaveLesson(lesson *models.Lesson) error
+	DeleteLesson(id uint) error // Along with its exercises and the learners' state for it
+
+	Exercise(id uint) (models.Exercise, error)
+	LessonExercises(lessonID uint) ([]models.Exercise, error) // In order
+	ExerciseWords(exerciseID uint) ([]uint, error)            // IDs of the linked vocabulary
+	SaveExercise(exercise *models.Exercise) error             // Replaces the links when Vocabulary is not nil
+	DeleteExercise(id uint) error                             // Along with its links and review schedule
+}
+
+// WordQuery selects vocabulary. Empty fields match everything.
+type WordQuery struct {
+	Category     string
+	Keys         []string
+	German       string // Case-insensitive
+	PartOfSpeech string
+	Nouns        bool // Only words with a gender
+}
+
+// VocabularyStore keeps the vocabulary
+type VocabularyStore interface {
+	Word(id uint) (models.Vocabulary, error)
+	Words(query WordQuery) ([]models.Vocabulary, error) // In ID order
+	SaveWord(word *models.Vocabulary) error
+}
+
+// ProgressStore keeps what learners have answered and how far they got
+type ProgressStore interface {
+	AddProgress(progress *models.Progress) error
+	ProgressSince(userID uint, since time.Time) ([]models.Progress, error) // Zero since for all
+	ResetProgress(userID uint) error                                       // Everything except the profile and settings
+
+	LessonState(userID, lessonID uint) (models.UserLesson, error)
+	LessonStates(userID uint) ([]models.UserLesson, error)
+	SaveLessonState(state *models.UserLesson) error
+
+	AddGenderAnswer(answer *models.GenderAnswer) error
+	GenderAnswers(userID uint) ([]models.GenderAnswer, error)
+	AddVocabAnswer(answer *models.VocabAnswer) error
+	VocabAnswers(userID uint) ([]models.VocabAnswer, error)
+
+	WordStrength(userID, vocabularyID uint) (models.WordStrength, error)
+	WordStrengths(userID uint) ([]models.WordStrength, error)
+	SaveWordStrength(strength *models.WordStrength) error
+}
+
+// ReviewStore keeps the spaced-repetition schedule
+type ReviewStore interface {
+	ReviewItem(userID uint, itemType string, itemID uint) (models.ReviewItem, error)
+	DueReviewItems(userID uint, now time.Time, limit int) ([]models.ReviewItem, error) // Most overdue first; limit <= 0 for all
+	SaveReviewItem(item *models.ReviewItem) error
+}
+
+// SettingStore keeps key/value settings. UserID 0 holds app-wide ones.
+type SettingStore interface {
+	Setting(userID uint, key string) (string, error)
+	SetSetting(userID uint, key, value string) error
+	DeleteSetting(userID uint, key string) error
+}
+
+var (
+	_ Store = (*Gorm)(nil)
+	_ Store = (*Memory)(nil)
+)
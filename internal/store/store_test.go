@@ -0,0 +1,384 @@
+package store_test
+
+import (
+	"duocli/internal/database"
+	"duocli/internal/models"
+	"duocli/internal/store"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// eachStore runs test against a migrated SQLite store and a memory store, so
+// that the two implementations are held to the same behaviour
+func eachStore(t *testing.T, test func(t *testing.T, s store.Store)) {
+	t.Run("gorm", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "duocli.db")
+		db, err := database.Connect(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := database.Migrate(db, path); err != nil {
+			t.Fatal(err)
+		}
+		test(t, store.NewGorm(db))
+	})
+	t.Run("memory", func(t *testing.T) {
+		test(t, store.NewMemory())
+	})
+}
+
+func TestUsers(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		user := models.User{Name: "Anna"}
+		if err := s.CreateUser(&user); err != nil {
+			t.Fatal(err)
+		}
+		if user.ID == 0 || user.Level != 1 || user.Hearts != 5 {
+			t.Errorf("CreateUser = %+v, want an ID, level 1 and 5 hearts", user)
+		}
+
+		found, err := s.UserByName("anna")
+		if err != nil || found.ID != user.ID {
+			t.Errorf("UserByName(anna) = %+v, %v, want user %d", found, err, user.ID)
+		}
+		if _, err := s.User(user.ID + 100); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("User(missing) error = %v, want ErrNotFound", err)
+		}
+
+		if err := s.SetSetting(user.ID, "hearts", "off"); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DeleteUser(user.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Setting(user.ID, "hearts"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Setting after DeleteUser error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestLessonsAndExercises(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		for _, lesson := range []models.Lesson{
+			{Title: "Second", Order: 2},
+			{Title: "Spanish", Order: 1, Language: "spanish"},
+			{Title: "First", Order: 1},
+		} {
+			if err := s.SaveLesson(&lesson); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		lessons, err := s.Lessons("german")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lessons) != 2 || lessons[0].Title != "First" || lessons[1].Title != "Second" {
+			t.Fatalf("Lessons(german) = %+v, want First then Second", lessons)
+		}
+		if all, _ := s.Lessons(""); len(all) != 3 {
+			t.Errorf("Lessons(\"\") returned %d lessons, want 3", len(all))
+		}
+		if lesson, err := s.LessonByOrder("spanish", 1); err != nil || lesson.Title != "Spanish" {
+			t.Errorf("LessonByOrder(spanish, 1) = %+v, %v", lesson, err)
+		}
+		if _, err := s.LessonByOrder("german", 3); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("LessonByOrder(german, 3) error = %v, want ErrNotFound", err)
+		}
+
+		hund := models.Vocabulary{German: "Hund", English: "dog"}
+		katze := models.Vocabulary{German: "Katze", English: "cat"}
+		for _, word := range []*models.Vocabulary{&hund, &katze} {
+			if err := s.SaveWord(word); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		lessonID := lessons[0].ID
+		late := models.Exercise{LessonID: lessonID, Order: 2, Question: "late", Vocabulary: []models.Vocabulary{katze, hund}}
+		early := models.Exercise{LessonID: lessonID, Order: 1, Question: "early"}
+		for _, exercise := range []*models.Exercise{&late, &early} {
+			if err := s.SaveExercise(exercise); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		exercises, err := s.LessonExercises(lessonID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(exercises) != 2 || exercises[0].Question != "early" || exercises[1].Question != "late" {
+			t.Errorf("LessonExercises = %+v, want early then late", exercises)
+		}
+
+		wantWords := func(label string, want ...uint) {
+			t.Helper()
+			ids, err := s.ExerciseWords(late.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ids) != len(want) {
+				t.Errorf("%s: ExerciseWords = %v, want %v", label, ids, want)
+				return
+			}
+			for i := range ids {
+				if ids[i] != want[i] {
+					t.Errorf("%s: ExerciseWords = %v, want %v", label, ids, want)
+					return
+				}
+			}
+		}
+		wantWords("saved", hund.ID, katze.ID)
+
+		// Nil vocabulary keeps the links, an empty list clears them
+		late.Vocabulary = nil
+		late.Question = "later"
+		if err := s.SaveExercise(&late); err != nil {
+			t.Fatal(err)
+		}
+		wantWords("nil vocabulary", hund.ID, katze.ID)
+		late.Vocabulary = []models.Vocabulary{}
+		if err := s.SaveExercise(&late); err != nil {
+			t.Fatal(err)
+		}
+		wantWords("empty vocabulary")
+	})
+}
+
+func TestWords(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		for _, word := range []models.Vocabulary{
+			{Key: "hund", German: "Hund", English: "dog", Category: "animals", PartOfSpeech: "noun", Gender: "der"},
+			{Key: "laufen", German: "laufen", English: "to run", Category: "verbs", PartOfSpeech: "verb"},
+			{Key: "katze", German: "Katze", English: "cat", Category: "animals", PartOfSpeech: "noun", Gender: "die"},
+		} {
+			if err := s.SaveWord(&word); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tests := []struct {
+			name  string
+			query store.WordQuery
+			want  []string
+		}{
+			{"all", store.WordQuery{}, []string{"Hund", "laufen", "Katze"}},
+			{"category", store.WordQuery{Category: "animals"}, []string{"Hund", "Katze"}},
+			{"keys", store.WordQuery{Keys: []string{"katze", "laufen"}}, []string{"laufen", "Katze"}},
+			{"german", store.WordQuery{German: "HUND"}, []string{"Hund"}},
+			{"part of speech", store.WordQuery{PartOfSpeech: "verb"}, []string{"laufen"}},
+			{"nouns", store.WordQuery{Nouns: true}, []string{"Hund", "Katze"}},
+			{"combined", store.WordQuery{Category: "animals", Keys: []string{"laufen"}}, nil},
+		}
+
+		for _, tt := range tests {
+			words, err := s.Words(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, word := range words {
+				got = append(got, word.German)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("%s: Words = %v, want %v", tt.name, got, tt.want)
+				continue
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("%s: Words = %v, want %v", tt.name, got, tt.want)
+					break
+				}
+			}
+		}
+	})
+}
+
+func TestProgressAndReviews(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		now := time.Now().Truncate(time.Second)
+		for _, p := range []models.Progress{
+			{UserID: 1, ExerciseID: 1, CompletedAt: now.Add(-48 * time.Hour)},
+			{UserID: 1, ExerciseID: 2, CompletedAt: now},
+			{UserID: 2, ExerciseID: 3, CompletedAt: now},
+		} {
+			if err := s.AddProgress(&p); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if rows, _ := s.ProgressSince(1, time.Time{}); len(rows) != 2 {
+			t.Errorf("ProgressSince(1, zero) returned %d rows, want 2", len(rows))
+		}
+		if rows, _ := s.ProgressSince(1, now.Add(-time.Hour)); len(rows) != 1 || rows[0].ExerciseID != 2 {
+			t.Errorf("ProgressSince(1, an hour ago) = %+v, want exercise 2", rows)
+		}
+
+		for _, item := range []models.ReviewItem{
+			{UserID: 1, ItemType: "exercise", ItemID: 1, DueAt: now.Add(-time.Hour)},
+			{UserID: 1, ItemType: "exercise", ItemID: 2, DueAt: now.Add(-48 * time.Hour)},
+			{UserID: 1, ItemType: "exercise", ItemID: 3, DueAt: now.Add(time.Hour)},
+			{UserID: 2, ItemType: "exercise", ItemID: 1, DueAt: now.Add(-time.Hour)},
+		} {
+			if err := s.SaveReviewItem(&item); err != nil {
+				t.Fatal(err)
+			}
+		}
+		due, err := s.DueReviewItems(1, now, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(due) != 2 || due[0].ItemID != 2 || due[1].ItemID != 1 {
+			t.Errorf("DueReviewItems = %+v, want items 2 then 1", due)
+		}
+		if due, _ := s.DueReviewItems(1, now, 1); len(due) != 1 || due[0].ItemID != 2 {
+			t.Errorf("DueReviewItems with limit 1 = %+v, want item 2", due)
+		}
+		if _, err := s.ReviewItem(1, "vocabulary", 1); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("ReviewItem(missing) error = %v, want ErrNotFound", err)
+		}
+
+		if err := s.ResetProgress(1); err != nil {
+			t.Fatal(err)
+		}
+		if rows, _ := s.ProgressSince(1, time.Time{}); len(rows) != 0 {
+			t.Errorf("ProgressSince after reset returned %d rows, want 0", len(rows))
+		}
+		if due, _ := s.DueReviewItems(1, now, 0); len(due) != 0 {
+			t.Errorf("DueReviewItems after reset returned %d items, want 0", len(due))
+		}
+		if rows, _ := s.ProgressSince(2, time.Time{}); len(rows) != 1 {
+			t.Errorf("ResetProgress(1) touched user 2: %d rows left, want 1", len(rows))
+		}
+	})
+}
+
+func TestSettingsAndTransactions(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		if _, err := s.Setting(0, "theme"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Setting(missing) error = %v, want ErrNotFound", err)
+		}
+		for _, value := range []string{"dark", "mono"} {
+			if err := s.SetSetting(0, "theme", value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if value, err := s.Setting(0, "theme"); err != nil || value != "mono" {
+			t.Errorf("Setting(theme) = %q, %v, want mono", value, err)
+		}
+
+		failed := errors.New("failed")
+		err := s.Transaction(func(tx store.Store) error {
+			if err := tx.SetSetting(0, "theme", "neon"); err != nil {
+				return err
+			}
+			return tx.SaveLesson(&models.Lesson{Title: "Kept", Order: 1})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = s.Transaction(func(tx store.Store) error {
+			if err := tx.SetSetting(0, "theme", "plain"); err != nil {
+				return err
+			}
+			if err := tx.SaveLesson(&models.Lesson{Title: "Lost", Order: 2}); err != nil {
+				return err
+			}
+			return failed
+		})
+		if !errors.Is(err, failed) {
+			t.Fatalf("Transaction error = %v, want %v", err, failed)
+		}
+		if value, _ := s.Setting(0, "theme"); value != "neon" {
+			t.Errorf("Setting after rollback = %q, want neon", value)
+		}
+		if lessons, _ := s.Lessons(""); len(lessons) != 1 {
+			t.Errorf("Lessons after rollback = %+v, want only the committed one", lessons)
+		}
+
+		if err := s.DeleteSetting(0, "theme"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Setting(0, "theme"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Setting after delete error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestDeleteLesson(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		lesson := models.Lesson{Title: "Dropped", Order: 1}
+		kept := models.Lesson{Title: "Kept", Order: 2}
+		for _, l := range []*models.Lesson{&lesson, &kept} {
+			if err := s.SaveLesson(l); err != nil {
+				t.Fatal(err)
+			}
+		}
+		word := models.Vocabulary{German: "Hund", English: "dog"}
+		if err := s.SaveWord(&word); err != nil {
+			t.Fatal(err)
+		}
+		exercise := models.Exercise{LessonID: lesson.ID, Question: "dog", Vocabulary: []models.Vocabulary{word}}
+		other := models.Exercise{LessonID: kept.ID, Question: "cat"}
+		for _, e := range []*models.Exercise{&exercise, &other} {
+			if err := s.SaveExercise(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, item := range []models.ReviewItem{
+			{UserID: 1, ItemType: "exercise", ItemID: exercise.ID},
+			{UserID: 1, ItemType: "exercise", ItemID: other.ID},
+			{UserID: 1, ItemType: "vocabulary", ItemID: exercise.ID},
+		} {
+			if err := s.SaveReviewItem(&item); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, state := range []models.UserLesson{
+			{UserID: 1, LessonID: lesson.ID, Status: models.LessonCompleted},
+			{UserID: 1, LessonID: kept.ID, Status: models.LessonInProgress},
+		} {
+			if err := s.SaveLessonState(&state); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := s.DeleteLesson(lesson.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := s.Lesson(lesson.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Lesson after delete error = %v, want ErrNotFound", err)
+		}
+		if _, err := s.Exercise(exercise.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("Exercise of deleted lesson error = %v, want ErrNotFound", err)
+		}
+		if ids, _ := s.ExerciseWords(exercise.ID); len(ids) != 0 {
+			t.Errorf("ExerciseWords of deleted exercise = %v, want none", ids)
+		}
+		if _, err := s.ReviewItem(1, "exercise", exercise.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("ReviewItem of deleted exercise error = %v, want ErrNotFound", err)
+		}
+		if _, err := s.LessonState(1, lesson.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("LessonState of deleted lesson error = %v, want ErrNotFound", err)
+		}
+
+		// Everything else stays
+		if _, err := s.Exercise(other.ID); err != nil {
+			t.Errorf("Exercise of kept lesson: %v", err)
+		}
+		if _, err := s.ReviewItem(1, "exercise", other.ID); err != nil {
+			t.Errorf("ReviewItem of kept exercise: %v", err)
+		}
+		if _, err := s.ReviewItem(1, "vocabulary", exercise.ID); err != nil {
+			t.Errorf("ReviewItem of a word sharing the exercise's ID: %v", err)
+		}
+		if _, err := s.LessonState(1, kept.ID); err != nil {
+			t.Errorf("LessonState of kept lesson: %v", err)
+		}
+		if _, err := s.Word(word.ID); err != nil {
+			t.Errorf("Word linked to deleted exercise: %v", err)
+		}
+	})
+}
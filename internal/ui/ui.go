@@ -1,15 +1,51 @@
 package ui
 
 import (
-	"duocli/internal/database"
+	"duocli/internal/config"
+	"duocli/internal/console"
+	"duocli/internal/grammar"
+	"duocli/internal/hearts"
 	"duocli/internal/models"
+	"duocli/internal/progress"
+	"duocli/internal/store"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 )
 
+// cfg holds the daily goal, theme and course the UI shows
+var cfg = config.Default()
+
+// Configure sets the configuration the UI runs with and applies its colour
+// theme. It is called once at startup.
+func Configure(c *config.Config) {
+	cfg = c
+	if cfg.Theme == "mono" {
+		color.NoColor = true
+	}
+}
+
+// CourseLessons returns the lessons of the configured course, in order
+func CourseLessons(s store.LessonStore) ([]models.Lesson, error) {
+	return s.Lessons(cfg.Course)
+}
+
+// ShowDailyGoal prints how many lesson exercises the user has answered today
+// against their daily goal
+func ShowDailyGoal(s store.ProgressStore, userID uint) {
+	done := int(progress.AnsweredToday(s, userID))
+	if done >= cfg.DailyGoal {
+		color.Green("🎯 Daily goal: %d/%d exercises — reached! 🎉", done, cfg.DailyGoal)
+		return
+	}
+	color.Yellow("🎯 Daily goal: %s %d/%d exercises", createProgressBar(done*100/cfg.DailyGoal, 10), done, cfg.DailyGoal)
+}
+
 func ShowWelcome() {
 	color.Cyan(`
 ╔══════════════════════════════════════╗
@@ -19,63 +55,73 @@ func ShowWelcome() {
 `)
 }
 
-func ShowUserProfile(userID uint) {
-	var user models.User
-	if err := database.DB.First(&user, userID).Error; err != nil {
+func ShowUserProfile(s store.Store, userID uint) {
+	user, err := s.User(userID)
+	if err != nil {
 		color.Red("User not found!")
 		return
 	}
 
-	// Get completed lessons count
-	var completedLessons int64
-	database.DB.Model(&models.Lesson{}).Where("is_completed = ?", true).Count(&completedLessons)
-
-	// Get total lessons count
-	var totalLessons int64
-	database.DB.Model(&models.Lesson{}).Count(&totalLessons)
+	// Count lessons of the configured course only, as ShowLessons lists them
+	lessons, _ := CourseLessons(s)
+	totalLessons := len(lessons)
+	completedLessons := progress.CompletedCount(s, userID, lessons)
 
 	// Calculate streak
 	streak := calculateStreak(user)
 
-	fmt.Println("\n" + strings.Repeat("=", 50))
+	console.Println("\n" + strings.Repeat("=", 50))
 	color.Cyan("👤 USER PROFILE")
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 	
 	color.White("Name: %s", user.Name)
 	color.Green("Level: %d", user.Level)
 	color.Yellow("XP: %d", user.XP)
 	color.Magenta("Streak: %d days 🔥", streak)
+	ShowDailyGoal(s, userID)
+	if hearts.Enabled(s, userID) {
+		now := time.Now()
+		hearts.Refresh(&user, now)
+		if next := hearts.NextIn(user, now); next > 0 {
+			color.Red("Hearts: %s (next in %s)", hearts.Display(user.Hearts), hearts.FormatWait(next))
+		} else {
+			color.Red("Hearts: %s", hearts.Display(user.Hearts))
+		}
+	}
 	color.Blue("Lessons Completed: %d/%d", completedLessons, totalLessons)
 	
 	// Progress bar
-	progress := float64(completedLessons) / float64(totalLessons) * 100
+	progress := float64(0)
+	if totalLessons > 0 {
+		progress = float64(completedLessons) / float64(totalLessons) * 100
+	}
 	progressBar := createProgressBar(int(progress), 30)
 	color.White("Progress: %s %.1f%%", progressBar, progress)
 	
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 }
 
-func ShowLessons() {
-	var lessons []models.Lesson
-	database.DB.Order("\"order\"").Find(&lessons)
+func ShowLessons(s store.Store, userID uint) {
+	lessons, _ := CourseLessons(s)
 
-	fmt.Println("\n" + strings.Repeat("=", 50))
+	console.Println("\n" + strings.Repeat("=", 50))
 	color.Cyan("📚 AVAILABLE LESSONS")
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 
 	for _, lesson := range lessons {
 		status := "🔒"
 		statusColor := color.RedString
 		
-		if lesson.IsCompleted {
+		state := progress.LessonState(s, userID, lesson.ID)
+		if state.Status == models.LessonCompleted {
 			status = "✅"
 			statusColor = color.GreenString
-		} else if isLessonUnlocked(lesson) {
+		} else if progress.IsUnlocked(s, userID, lesson) {
 			status = "🔓"
 			statusColor = color.YellowString
 		}
 
-		fmt.Printf("%s %s Level %d: %s\n", 
+		console.Printf("%s %s Level %d: %s\n",
 			status, 
 			statusColor("Lesson %d", lesson.Order), 
 			lesson.Level, 
@@ -83,40 +129,117 @@ func ShowLessons() {
 		)
 		color.White("   📝 %s", lesson.Description)
 		color.Yellow("   💰 XP Reward: %d", lesson.XPReward)
-		fmt.Println()
+		if state.Attempts > 0 {
+			color.Blue("   🏅 Best Score: %.1f%% (%d attempts)", state.BestScore, state.Attempts)
+		}
+		console.Println()
 	}
 	
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 }
 
-func ShowVocabulary(category string) {
-	var vocab []models.Vocabulary
-	query := database.DB.Order("difficulty, german")
-	
-	if category != "" {
-		query = query.Where("category = ?", category)
+// VocabFilter selects which words ShowVocabulary lists
+type VocabFilter int
+
+const (
+	AllWords      VocabFilter = iota
+	WeakWords                 // Practised words below progress.WeakStrength, weakest first
+	MasteredWords             // Words at progress.MasteredStrength or above, strongest first
+)
+
+func ShowVocabulary(s store.Store, userID uint, category string, filter VocabFilter) {
+	vocab, _ := s.Words(store.WordQuery{Category: category})
+	sort.SliceStable(vocab, func(i, j int) bool {
+		if vocab[i].Difficulty != vocab[j].Difficulty {
+			return vocab[i].Difficulty < vocab[j].Difficulty
+		}
+		return vocab[i].German < vocab[j].German
+	})
+
+	strengths := progress.Strengths(s, userID, time.Now())
+	if filter != AllWords {
+		var filtered []models.Vocabulary
+		for _, word := range vocab {
+			strength, seen := strengths[word.ID]
+			if filter == WeakWords && seen && strength < progress.WeakStrength ||
+				filter == MasteredWords && strength >= progress.MasteredStrength {
+				filtered = append(filtered, word)
+			}
+		}
+		sort.SliceStable(filtered, func(i, j int) bool {
+			if filter == WeakWords {
+				return strengths[filtered[i].ID] < strengths[filtered[j].ID]
+			}
+			return strengths[filtered[i].ID] > strengths[filtered[j].ID]
+		})
+		vocab = filtered
 	}
-	
-	query.Find(&vocab)
 
-	fmt.Println("\n" + strings.Repeat("=", 50))
+	title := "ALL VOCABULARY"
 	if category != "" {
-		color.Cyan("📖 VOCABULARY - %s", strings.ToUpper(category))
-	} else {
-		color.Cyan("📖 ALL VOCABULARY")
+		title = "VOCABULARY - " + strings.ToUpper(category)
+	}
+	switch filter {
+	case WeakWords:
+		title = "💪 WEAK WORDS"
+	case MasteredWords:
+		title = "🏆 MASTERED WORDS"
+	}
+	if filter != AllWords && category != "" {
+		title += " - " + strings.ToUpper(category)
+	}
+
+	console.Println("\n" + strings.Repeat("=", 50))
+	color.Cyan("📖 %s", title)
+	console.Println(strings.Repeat("=", 50))
+
+	if len(vocab) == 0 {
+		switch filter {
+		case WeakWords:
+			color.Green("🎉 No weak words right now. Keep practising!")
+		case MasteredWords:
+			color.Yellow("No mastered words yet. Try ./duocli practice vocab")
+		default:
+			color.Yellow("No words found.")
+		}
+		console.Println(strings.Repeat("=", 50))
+		return
 	}
-	fmt.Println(strings.Repeat("=", 50))
 
 	currentCategory := ""
 	for _, word := range vocab {
-		if word.Category != currentCategory {
+		if filter == AllWords && word.Category != currentCategory {
 			currentCategory = word.Category
 			color.Blue("\n🏷️  %s", strings.ToUpper(currentCategory))
-			fmt.Println(strings.Repeat("-", 30))
+			console.Println(strings.Repeat("-", 30))
 		}
 		
 		difficulty := strings.Repeat("⭐", word.Difficulty)
-		color.White("🇩🇪 %-15s 🇺🇸 %-15s %s", word.German, word.English, difficulty)
+		plural := ""
+		if word.Plural != "" {
+			plural = "pl. " + ColorGender(grammar.DefiniteArticle(grammar.Nominative, grammar.Plural)) + " " + word.Plural
+		}
+		color.White("🇩🇪 %s %s 🇺🇸 %-18s %s", pad(ColorWord(word), 22), pad(plural, 20), word.English, difficulty)
+
+		var details []string
+		if word.PartOfSpeech != "" {
+			details = append(details, word.PartOfSpeech)
+		}
+		if word.Past != "" && word.Participle != "" {
+			details = append(details, fmt.Sprintf("%s, %s, %s", word.German, word.Past, word.Participle))
+		}
+		if word.SeparablePrefix != "" {
+			details = append(details, "separable: "+word.SeparablePrefix+"-")
+		}
+		if word.IPA != "" {
+			details = append(details, "🔊 /"+strings.Trim(word.IPA, "/")+"/")
+		}
+		if len(details) > 0 {
+			color.Blue("   %s", strings.Join(details, " · "))
+		}
+		if strength, seen := strengths[word.ID]; seen {
+			color.White("   💪 Strength: %s %d%%", createProgressBar(int(strength*100), 10), int(strength*100))
+		}
 		
 		if word.Example != "" {
 			color.Yellow("   💬 %s", word.Example)
@@ -124,47 +247,92 @@ func ShowVocabulary(category string) {
 				color.White("   📝 %s", word.Translation)
 			}
 		}
-		fmt.Println()
+		console.Println()
 	}
 	
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
+}
+
+// ColorGender renders an article in its gender colour: blue for der, red
+// for die, green for das
+func ColorGender(article string) string {
+	switch article {
+	case grammar.Masculine:
+		return color.BlueString(article)
+	case grammar.Feminine:
+		return color.RedString(article)
+	case grammar.Neuter:
+		return color.GreenString(article)
+	}
+	return article
+}
+
+// ColorWord renders a vocabulary word with its article in gender colour
+func ColorWord(word models.Vocabulary) string {
+	if word.Gender == "" {
+		return word.German
+	}
+	return ColorGender(word.Gender) + " " + word.German
 }
 
-func ShowStats(userID uint) {
-	var user models.User
-	database.DB.First(&user, userID)
+// pad fills s with spaces up to width visible characters, ignoring colour
+// codes
+func pad(s string, width int) string {
+	visible := utf8.RuneCountInString(ansiCodes.ReplaceAllString(s, ""))
+	if visible >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-visible)
+}
+
+var ansiCodes = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func ShowStats(s store.ProgressStore, userID uint) {
+	rows, _ := s.ProgressSince(userID, time.Time{})
 
 	// Get progress data
-	var totalExercises int64
-	var correctExercises int64
-	
-	database.DB.Model(&models.Progress{}).Where("user_id = ?", userID).Count(&totalExercises)
-	database.DB.Model(&models.Progress{}).Where("user_id = ? AND is_correct = ?", userID, true).Count(&correctExercises)
+	totalExercises := len(rows)
+	var correctExercises int
+	var credit float64        // Partly right answers, such as matching pairs, count for their share
+	var firstTryExercises int // Answers that were right without needing a retry
+	recentProgress := 0
+	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
+	for _, row := range rows {
+		credit += row.Credit
+		if row.IsCorrect {
+			correctExercises++
+			if row.Attempts <= 1 {
+				firstTryExercises++
+			}
+		}
+		// Recent activity (last 7 days)
+		if row.CompletedAt.After(sevenDaysAgo) {
+			recentProgress++
+		}
+	}
 	
 	accuracy := float64(0)
+	firstTryAccuracy := float64(0)
 	if totalExercises > 0 {
-		accuracy = float64(correctExercises) / float64(totalExercises) * 100
+		accuracy = credit / float64(totalExercises) * 100
+		firstTryAccuracy = float64(firstTryExercises) / float64(totalExercises) * 100
 	}
 
-	// Get recent activity (last 7 days)
-	var recentProgress []models.Progress
-	sevenDaysAgo := time.Now().AddDate(0, 0, -7)
-	database.DB.Where("user_id = ? AND completed_at > ?", userID, sevenDaysAgo).Find(&recentProgress)
-
-	fmt.Println("\n" + strings.Repeat("=", 50))
+	console.Println("\n" + strings.Repeat("=", 50))
 	color.Cyan("📊 LEARNING STATISTICS")
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 	
 	color.White("Total Exercises Completed: %d", totalExercises)
 	color.Green("Correct Answers: %d", correctExercises)
 	color.Yellow("Accuracy: %.1f%%", accuracy)
-	color.Blue("Recent Activity (7 days): %d exercises", len(recentProgress))
+	color.Yellow("First-try Accuracy: %.1f%%", firstTryAccuracy)
+	color.Blue("Recent Activity (7 days): %d exercises", recentProgress)
 	
 	// Show accuracy bar
 	accuracyBar := createProgressBar(int(accuracy), 30)
 	color.White("Accuracy: %s %.1f%%", accuracyBar, accuracy)
 	
-	fmt.Println(strings.Repeat("=", 50))
+	console.Println(strings.Repeat("=", 50))
 }
 
 func createProgressBar(percentage, width int) string {
@@ -198,17 +366,127 @@ func calculateStreak(user models.User) int {
 	return 0
 }
 
-func isLessonUnlocked(lesson models.Lesson) bool {
-	if lesson.Order == 1 {
-		return true // First lesson is always unlocked
+// ShowGenderStats prints the user's noun gender accuracy, the articles they
+// mix up and the nouns they miss most
+func ShowGenderStats(s store.Store, userID uint) {
+	totals := progress.GenderTotals(s, userID)
+
+	console.Println("\n" + strings.Repeat("=", 50))
+	color.Cyan("🎯 GENDER STATISTICS")
+	console.Println(strings.Repeat("=", 50))
+
+	if len(totals) == 0 {
+		color.Yellow("No gender answers yet. Try 'duocli drill gender'.")
+		console.Println(strings.Repeat("=", 50))
+		return
 	}
-	
-	// Check if previous lesson is completed
-	var prevLesson models.Lesson
-	err := database.DB.Where("\"order\" = ?", lesson.Order-1).First(&prevLesson).Error
-	if err != nil {
-		return false
+
+	for _, article := range grammar.Genders {
+		total := totals[article]
+		if total.Answers == 0 {
+			continue
+		}
+		bar := createProgressBar(int(total.Accuracy()), 20)
+		color.White("%s nouns: %s %.0f%% (%d/%d)", article, bar, total.Accuracy(), total.Correct, total.Answers)
 	}
-	
-	return prevLesson.IsCompleted
-}
\ No newline at end of file
+
+	confusions := progress.GenderConfusions(s, userID)
+	sort.Slice(confusions, func(i, j int) bool {
+		return confusions[i].Rate() > confusions[j].Rate()
+	})
+	if len(confusions) > 0 {
+		color.Cyan("\n🔀 Confusions")
+		for _, confusion := range confusions {
+			color.Yellow("You confuse %s→%s %.0f%% of the time", confusion.Expected, confusion.Given, confusion.Rate())
+		}
+	}
+
+	var weak []progress.WordResult
+	for _, stat := range progress.NounGenders(s, userID) {
+		if stat.Correct < stat.Answers {
+			weak = append(weak, stat)
+		}
+	}
+	sort.Slice(weak, func(i, j int) bool {
+		return weak[i].Accuracy() < weak[j].Accuracy()
+	})
+	if len(weak) > 5 {
+		weak = weak[:5]
+	}
+	if len(weak) > 0 {
+		color.Cyan("\n📉 Nouns to practise")
+		for _, stat := range weak {
+			word, _ := s.Word(stat.VocabularyID)
+			color.White("%s %.0f%% (%d/%d)", pad(ColorWord(word), 20), stat.Accuracy(), stat.Correct, stat.Answers)
+		}
+	}
+
+	console.Println(strings.Repeat("=", 50))
+}
+
+// ShowConjugation prints a verb's forms in every tense, one row per person
+func ShowConjugation(c grammar.Conjugation) {
+	console.Println("\n" + strings.Repeat("=", 70))
+	kind := "regular"
+	if c.Irregular {
+		kind = "irregular"
+	}
+	color.Cyan("🔧 %s (%s)", c.Infinitive, kind)
+	color.White("Partizip II: %s · Perfekt with %s", c.Participle, c.Auxiliary)
+	console.Println(strings.Repeat("=", 70))
+
+	header := fmt.Sprintf("%-10s", "")
+	for _, tense := range grammar.Tenses {
+		header += fmt.Sprintf(" %-19s", tense.Title())
+	}
+	color.Blue("%s", strings.TrimRight(header, " "))
+	console.Println(strings.Repeat("-", 70))
+
+	for i, person := range grammar.Persons {
+		row := fmt.Sprintf("%-10s", person)
+		for _, tense := range grammar.Tenses {
+			row += fmt.Sprintf(" %-19s", c.Forms[tense][i])
+		}
+		color.White("%s", strings.TrimRight(row, " "))
+	}
+
+	console.Println(strings.Repeat("=", 70))
+}
+
+// ShowDeclension prints a noun with definite and indefinite articles in
+// every case, optionally with an adjective, and in the plural when known
+func ShowDeclension(gender, noun, plural, adjective string) {
+	console.Println("\n" + strings.Repeat("=", 70))
+	color.Cyan("📐 %s %s", ColorGender(gender), noun)
+	console.Println(strings.Repeat("=", 70))
+
+	kinds := []grammar.ArticleKind{grammar.Definite, grammar.Indefinite}
+	header := fmt.Sprintf("%-10s %-22s %-22s", "", "Definite", "Indefinite")
+	if adjective != "" {
+		kinds = append(kinds, grammar.NoArticle)
+		header += fmt.Sprintf(" %-22s", "No article")
+	}
+
+	rows := func(title, gender, noun string) {
+		color.Blue("\n%s", strings.TrimRight(fmt.Sprintf("%-10s", title)+header[10:], " "))
+		console.Println(strings.Repeat("-", 70))
+		for _, c := range grammar.Cases {
+			row := fmt.Sprintf("%-10s", c.Title())
+			for _, kind := range kinds {
+				phrase := grammar.Phrase(kind, c, gender, adjective, noun)
+				if kind == grammar.Indefinite && gender == grammar.Plural && adjective == "" {
+					phrase = "—"
+				}
+				row += fmt.Sprintf(" %-22s", phrase)
+			}
+			color.White("%s", strings.TrimRight(row, " "))
+		}
+	}
+
+	rows("Singular", gender, noun)
+	if plural != "" {
+		rows("Plural", grammar.Plural, plural)
+	}
+
+	console.Println(strings.Repeat("=", 70))
+}
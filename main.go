@@ -1,17 +1,8 @@
 package main
 
-import (
-	"duocli/cmd"
-	"duocli/internal/database"
-	"log"
-)
+import "duocli/cmd"
 
 func main() {
-	// Initialize database
-	if err := database.InitDB(); err != nil {
-		log.Fatal("Failed to initialize database:", err)
-	}
-	
-	// Execute CLI
+	// The database is opened once flags are parsed, see cmd.openDatabase
 	cmd.Execute()
-}
\ No newline at end of file
+}